@@ -0,0 +1,112 @@
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DeepCopy returns a copy of src that shares no pointers, slices or maps with it,
+// so it can be used to snapshot and restore a shared request.
+//
+// Limitations:
+//   - Unexported struct fields are copied shallowly, pointers or maps behind them stay shared.
+//   - Non-nil channels cannot be copied and result in an error.
+//   - Functions and unsafe pointers are copied by reference.
+//   - Pointer cycles are preserved, every pointer is cloned only once.
+func DeepCopy[T any](src T) (T, error) {
+	var dst T
+	srcVal := reflect.ValueOf(&src).Elem()
+	dstVal := reflect.ValueOf(&dst).Elem()
+	if err := copyValue(dstVal, srcVal, map[visitKey]reflect.Value{}); err != nil {
+		var zero T
+		return zero, err
+	}
+	return dst, nil
+}
+
+// visitKey identifies a cloned pointer. The type is part of the key because a pointer to a
+// struct and a pointer to its first field, or two zero-size values, can share an address.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// copyValue deep copies src into the settable dst.
+// visited maps already cloned pointers to their copies.
+func copyValue(dst, src reflect.Value, visited map[visitKey]reflect.Value) error {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return nil
+		}
+		key := visitKey{ptr: src.Pointer(), typ: src.Type()}
+		if cloned, ok := visited[key]; ok {
+			dst.Set(cloned)
+			return nil
+		}
+		ptr := reflect.New(src.Type().Elem())
+		visited[key] = ptr
+		if err := copyValue(ptr.Elem(), src.Elem(), visited); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+	case reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		if err := copyValue(elem, src.Elem(), visited); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case reflect.Struct:
+		// Copy everything shallowly first so unexported fields are carried over
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if !src.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := copyValue(dst.Field(i), src.Field(i), visited); err != nil {
+				return fmt.Errorf("%s.%w", src.Type().Field(i).Name, err)
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil
+		}
+		slice := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		for i := 0; i < src.Len(); i++ {
+			if err := copyValue(slice.Index(i), src.Index(i), visited); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			if err := copyValue(dst.Index(i), src.Index(i), visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return nil
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			val := reflect.New(src.Type().Elem()).Elem()
+			if err := copyValue(val, iter.Value(), visited); err != nil {
+				return err
+			}
+			m.SetMapIndex(iter.Key(), val)
+		}
+		dst.Set(m)
+	case reflect.Chan:
+		if !src.IsNil() {
+			return fmt.Errorf("cannot deep copy %s", src.Type())
+		}
+	default:
+		dst.Set(src)
+	}
+	return nil
+}
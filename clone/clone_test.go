@@ -0,0 +1,110 @@
+package clone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string
+}
+
+type order struct {
+	ID       int64
+	Tags     []string
+	Items    map[string]int
+	Address  *address
+	Extra    any
+	Matrix   [2][]int
+	internal *address
+	Parent   *order
+}
+
+func TestDeepCopy(t *testing.T) {
+	shared := &address{City: "internal"}
+	src := order{
+		ID:       1,
+		Tags:     []string{"a", "b"},
+		Items:    map[string]int{"apple": 2},
+		Address:  &address{City: "Pune"},
+		Extra:    []int{1, 2},
+		Matrix:   [2][]int{{1}, {2}},
+		internal: shared,
+	}
+	src.Parent = &src
+
+	dst, err := DeepCopy(src)
+	assert.NoError(t, err)
+	assert.Equal(t, src.ID, dst.ID)
+	assert.Equal(t, src.Tags, dst.Tags)
+	assert.Equal(t, src.Items, dst.Items)
+	assert.Equal(t, *src.Address, *dst.Address)
+
+	// Mutating the copy leaves the source untouched
+	dst.Tags[0] = "changed"
+	dst.Items["apple"] = 10
+	dst.Address.City = "Mumbai"
+	dst.Extra.([]int)[0] = 100
+	dst.Matrix[0][0] = 100
+
+	assert.Equal(t, []string{"a", "b"}, src.Tags)
+	assert.Equal(t, 2, src.Items["apple"])
+	assert.Equal(t, "Pune", src.Address.City)
+	assert.Equal(t, []int{1, 2}, src.Extra)
+	assert.Equal(t, 1, src.Matrix[0][0])
+
+	// Unexported fields are copied shallowly
+	assert.Same(t, shared, dst.internal)
+
+	// Cycles are preserved without sharing the original pointer
+	assert.NotSame(t, src.Parent, dst.Parent)
+	assert.Same(t, dst.Parent, dst.Parent.Parent)
+}
+
+func TestDeepCopyNilValues(t *testing.T) {
+	dst, err := DeepCopy(order{})
+	assert.NoError(t, err)
+	assert.Equal(t, order{}, dst)
+
+	ptr, err := DeepCopy[*order](nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ptr)
+}
+
+func TestDeepCopyChannel(t *testing.T) {
+	type withChan struct {
+		Events chan int
+	}
+
+	_, err := DeepCopy(withChan{Events: make(chan int)})
+	assert.ErrorContains(t, err, "Events")
+
+	_, err = DeepCopy(withChan{})
+	assert.NoError(t, err)
+}
+
+func TestDeepCopyAliasedInteriorPointers(t *testing.T) {
+	type inner struct {
+		X int
+	}
+	type holder struct {
+		P *inner
+		Q *int
+	}
+	type empty struct{}
+	type zeroSized struct {
+		A *empty
+		B *struct{}
+	}
+
+	a := &inner{X: 7}
+	dst, err := DeepCopy(holder{P: a, Q: &a.X})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, dst.P.X)
+	assert.Equal(t, 7, *dst.Q)
+	assert.NotSame(t, a, dst.P)
+
+	_, err = DeepCopy(zeroSized{A: &empty{}, B: &struct{}{}})
+	assert.NoError(t, err)
+}
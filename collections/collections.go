@@ -0,0 +1,94 @@
+package collections
+
+// Stack is a LIFO collection backed by a slice.
+// The zero value is an empty stack ready to use.
+type Stack[T any] struct {
+	items []T
+}
+
+// NewStack returns a stack holding items, the last item being the top
+func NewStack[T any](items ...T) *Stack[T] {
+	return &Stack[T]{items: append([]T(nil), items...)}
+}
+
+// Push adds item to the top of the stack
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+// Pop removes and returns the top item, ok is false if the stack is empty
+func (s *Stack[T]) Pop() (item T, ok bool) {
+	if len(s.items) == 0 {
+		return item, false
+	}
+	last := len(s.items) - 1
+	item = s.items[last]
+	var zero T
+	s.items[last] = zero
+	s.items = s.items[:last]
+	return item, true
+}
+
+// Peek returns the top item without removing it, ok is false if the stack is empty
+func (s *Stack[T]) Peek() (item T, ok bool) {
+	if len(s.items) == 0 {
+		return item, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Len returns the number of items in the stack
+func (s *Stack[T]) Len() int {
+	return len(s.items)
+}
+
+// Queue is a FIFO collection backed by a slice.
+// The zero value is an empty queue ready to use.
+type Queue[T any] struct {
+	items []T
+	head  int
+}
+
+// NewQueue returns a queue holding items, the first item being the front
+func NewQueue[T any](items ...T) *Queue[T] {
+	return &Queue[T]{items: append([]T(nil), items...)}
+}
+
+// Push adds item to the back of the queue
+func (q *Queue[T]) Push(item T) {
+	q.items = append(q.items, item)
+}
+
+// Pop removes and returns the front item, ok is false if the queue is empty
+func (q *Queue[T]) Pop() (item T, ok bool) {
+	if q.head == len(q.items) {
+		return item, false
+	}
+	item = q.items[q.head]
+	var zero T
+	q.items[q.head] = zero
+	q.head++
+
+	// Reclaim the consumed prefix once it dominates the backing slice
+	if q.head == len(q.items) {
+		q.items = q.items[:0]
+		q.head = 0
+	} else if q.head > len(q.items)/2 {
+		q.items = append(q.items[:0], q.items[q.head:]...)
+		q.head = 0
+	}
+	return item, true
+}
+
+// Peek returns the front item without removing it, ok is false if the queue is empty
+func (q *Queue[T]) Peek() (item T, ok bool) {
+	if q.head == len(q.items) {
+		return item, false
+	}
+	return q.items[q.head], true
+}
+
+// Len returns the number of items in the queue
+func (q *Queue[T]) Len() int {
+	return len(q.items) - q.head
+}
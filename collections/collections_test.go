@@ -0,0 +1,80 @@
+package collections
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack(t *testing.T) {
+	t.Run("LIFO ordering", func(t *testing.T) {
+		s := NewStack(1, 2)
+		s.Push(3)
+		assert.Equal(t, 3, s.Len())
+
+		top, ok := s.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 3, top)
+
+		var popped []int
+		for s.Len() > 0 {
+			item, ok := s.Pop()
+			assert.True(t, ok)
+			popped = append(popped, item)
+		}
+		assert.Equal(t, []int{3, 2, 1}, popped)
+	})
+
+	t.Run("empty pop", func(t *testing.T) {
+		var s Stack[string]
+		item, ok := s.Pop()
+		assert.False(t, ok)
+		assert.Equal(t, "", item)
+
+		_, ok = s.Peek()
+		assert.False(t, ok)
+	})
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("FIFO ordering", func(t *testing.T) {
+		q := NewQueue(1, 2)
+		q.Push(3)
+		assert.Equal(t, 3, q.Len())
+
+		front, ok := q.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 1, front)
+
+		var popped []int
+		for q.Len() > 0 {
+			item, ok := q.Pop()
+			assert.True(t, ok)
+			popped = append(popped, item)
+		}
+		assert.Equal(t, []int{1, 2, 3}, popped)
+	})
+
+	t.Run("interleaved push and pop", func(t *testing.T) {
+		var q Queue[int]
+		var popped []int
+		for i := 0; i < 10; i++ {
+			q.Push(i)
+			q.Push(i + 100)
+			item, _ := q.Pop()
+			popped = append(popped, item)
+		}
+		assert.Equal(t, 10, q.Len())
+		assert.Equal(t, []int{0, 100, 1, 101, 2, 102, 3, 103, 4, 104}, popped)
+	})
+
+	t.Run("empty pop", func(t *testing.T) {
+		var q Queue[string]
+		item, ok := q.Pop()
+		assert.False(t, ok)
+		assert.Equal(t, "", item)
+
+		_, ok = q.Peek()
+		assert.False(t, ok)
+	})
+}
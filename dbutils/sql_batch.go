@@ -0,0 +1,97 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxRowsPerStatement is the number of rows BatchInsert puts in one statement by default
+const DefaultMaxRowsPerStatement = 500
+
+// BatchOption customizes how BatchInsert builds its statements
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	maxRows     int
+	placeholder func(n int) string
+}
+
+// WithMaxRowsPerStatement splits batches into statements of at most n rows
+func WithMaxRowsPerStatement(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxRows = n
+	}
+}
+
+// WithDollarPlaceholders numbers the placeholders $1, $2, ... as expected by Postgres drivers
+func WithDollarPlaceholders() BatchOption {
+	return func(o *batchOptions) {
+		o.placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+}
+
+// BatchInsert inserts rows into table with multi-row INSERT statements and returns the total rows affected.
+// Every row must hold one value per column. Values are passed as query arguments, table and
+// column names are written as is so they must not come from user input.
+func BatchInsert(ctx context.Context, txn *sql.Tx, table string, columns []string, rows [][]any, opts ...BatchOption) (int64, error) {
+	options := batchOptions{
+		maxRows:     DefaultMaxRowsPerStatement,
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.maxRows <= 0 {
+		options.maxRows = DefaultMaxRowsPerStatement
+	}
+	if len(columns) == 0 {
+		return 0, errors.New("batch insert needs at least one column")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("row %d: has %d values for %d columns", i, len(row), len(columns))
+		}
+	}
+
+	var total int64
+	for start := 0; start < len(rows); start += options.maxRows {
+		end := min(start+options.maxRows, len(rows))
+		query, args := buildBatchInsert(table, columns, rows[start:end], options.placeholder)
+		res, err := txn.ExecContext(ctx, query, args...)
+		if err != nil {
+			return total, fmt.Errorf("rows %d-%d: %w", start, end-1, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += affected
+	}
+	return total, nil
+}
+
+// buildBatchInsert builds a single INSERT statement for rows with its flattened arguments
+func buildBatchInsert(table string, columns []string, rows [][]any, placeholder func(n int) string) (string, []any) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for j, value := range row {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			sb.WriteString(placeholder(len(args)))
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String(), args
+}
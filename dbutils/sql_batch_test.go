@@ -0,0 +1,72 @@
+package dbutils
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users (name, age) VALUES (?, ?), (?, ?)")).
+		WithArgs("Alice", 25, "Bob", 30).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users (name, age) VALUES (?, ?)")).
+		WithArgs("Carol", 35).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	txn, err := db.Begin()
+	assert.NoError(t, err)
+	affected, err := BatchInsert(context.Background(), txn, "users", []string{"name", "age"}, [][]any{
+		{"Alice", 25},
+		{"Bob", 30},
+		{"Carol", 35},
+	}, WithMaxRowsPerStatement(2))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	assert.NoError(t, txn.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchInsert_DollarPlaceholders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users (name, age) VALUES ($1, $2), ($3, $4)")).
+		WithArgs("Alice", 25, "Bob", 30).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectRollback()
+
+	txn, err := db.Begin()
+	assert.NoError(t, err)
+	affected, err := BatchInsert(context.Background(), txn, "users", []string{"name", "age"}, [][]any{
+		{"Alice", 25},
+		{"Bob", 30},
+	}, WithDollarPlaceholders())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+	assert.NoError(t, txn.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchInsert_RowLengthMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	txn, err := db.Begin()
+	assert.NoError(t, err)
+	_, err = BatchInsert(context.Background(), txn, "users", []string{"name", "age"}, [][]any{{"Alice"}})
+	assert.EqualError(t, err, "row 0: has 1 values for 2 columns")
+}
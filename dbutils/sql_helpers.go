@@ -3,25 +3,72 @@ package dbutils
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
+	"time"
+
+	"github.com/mahadev-k/go-utils/internal/numconv"
 )
 
+// RowErrorHandler decides what happens when a row fails to scan.
+// Returning true skips the row and continues, false aborts with the error.
+type RowErrorHandler func(rowIndex int, err error) bool
+
+// sqlRows is the subset of *sql.Rows used by the row mappers
+type sqlRows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
 // MapSqlRows maps rows from a SQL query to a slice of map[string]interface{}
 func MapSqlRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	return mapSqlRows(rows, nil)
+}
+
+// MapSqlRowsWithHandler maps rows like MapSqlRows but hands every row scan error
+// to onRowError, which can skip the bad row instead of failing the whole result.
+// rowIndex is the 0-based position of the row in the result set.
+func MapSqlRowsWithHandler(rows *sql.Rows, onRowError RowErrorHandler) ([]map[string]interface{}, error) {
+	return mapSqlRows(rows, onRowError)
+}
+
+// StreamSqlRows maps rows like MapSqlRows but hands each row to fn as soon as it is
+// scanned instead of collecting them, so memory stays bounded for large result sets.
+// It stops at the first error returned by fn and returns it.
+func StreamSqlRows(rows *sql.Rows, fn func(row map[string]interface{}) error) error {
+	return streamSqlRows(rows, nil, fn)
+}
+
+func mapSqlRows(rows sqlRows, onRowError RowErrorHandler) ([]map[string]interface{}, error) {
+	// Result slice
+	var results []map[string]interface{}
+	err := streamSqlRows(rows, onRowError, func(row map[string]interface{}) error {
+		results = append(results, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the result
+	return results, nil
+}
+
+func streamSqlRows(rows sqlRows, onRowError RowErrorHandler, fn func(row map[string]interface{}) error) error {
 	defer rows.Close()
 
 	// Get column names
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Result slice
-	var results []map[string]interface{}
-
 	// Iterate over rows
-	for rows.Next() {
+	for rowIndex := 0; rows.Next(); rowIndex++ {
 		// Create a slice of interface{} to hold column values
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -33,7 +80,10 @@ func MapSqlRows(rows *sql.Rows) ([]map[string]interface{}, error) {
 
 		// Scan the row
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
+			if onRowError != nil && onRowError(rowIndex, err) {
+				continue
+			}
+			return err
 		}
 
 		// Create a map for this row
@@ -49,34 +99,59 @@ func MapSqlRows(rows *sql.Rows) ([]map[string]interface{}, error) {
 			}
 		}
 
-		results = append(results, rowMap)
+		if err := fn(rowMap); err != nil {
+			return err
+		}
 	}
 
 	// Check for errors during iteration
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+	return rows.Err()
+}
 
-	// Return the result
-	return results, nil
+// MapOption customizes how MapToStruct converts values
+type MapOption func(*mapOptions)
+
+type mapOptions struct {
+	timeLayouts []string
+}
+
+// WithTimeLayouts sets the layouts MapToStruct uses to parse string and []byte values into
+// time.Time fields, trying each in order until one matches. RFC3339 is used by default.
+func WithTimeLayouts(layouts ...string) MapOption {
+	return func(o *mapOptions) {
+		o.timeLayouts = append(o.timeLayouts, layouts...)
+	}
 }
 
-// MapToStruct maps a map[string]interface{} to a struct
-func MapToStruct[T any](data map[string]interface{}) (dest *T, err error) { 
-	// Validate that dest is a pointer to a struct
-	destVal := reflect.ValueOf(dest)
-	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Struct {
-		err = errors.New("dest must be a pointer to a struct")
-		return
+// MapToStruct maps a map[string]interface{} to a struct.
+// Values are converted to the field type where it is lossless, so an int64 column fits an int field.
+// Fields implementing sql.Scanner, such as sql.NullString, scan the raw value.
+// NULL values leave fields at their zero value.
+func MapToStruct[T any](data map[string]interface{}, opts ...MapOption) (dest *T, err error) { 
+	var options mapOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	timeLayouts := options.timeLayouts
+	if len(timeLayouts) == 0 {
+		timeLayouts = defaultTimeLayouts
 	}
 
-	destVal = destVal.Elem()
+	// Validate that T is a struct
+	dest = new(T)
+	destVal := reflect.ValueOf(dest).Elem()
+	if destVal.Kind() != reflect.Struct {
+		return nil, errors.New("dest must be a pointer to a struct")
+	}
 	destType := destVal.Type()
 
 	// Iterate over struct fields and set values from the map
 	for i := 0; i < destVal.NumField(); i++ {
 		field := destVal.Field(i)
 		fieldType := destType.Field(i)
+		if !field.CanSet() {
+			continue
+		}
 
 		// Get field name or JSON tag
 		mapKey := fieldType.Name
@@ -86,24 +161,162 @@ func MapToStruct[T any](data map[string]interface{}) (dest *T, err error) {
 
 		// Find the value in the map
 		if value, exists := data[mapKey]; exists {
-			if value != nil {
-				val := reflect.ValueOf(value)
-
-				// Ensure the types are compatible
-				if field.Kind() == val.Kind() || (field.Kind() == reflect.Ptr && field.Type().Elem() == val.Type()) {
-					if field.Kind() == reflect.Ptr {
-						ptr := reflect.New(field.Type().Elem())
-						ptr.Elem().Set(val)
-						field.Set(ptr)
-					} else {
-						field.Set(val)
+			// Nullable types such as sql.NullString scan the raw value, NULL included
+			if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+				if scanErr := scanner.Scan(value); scanErr != nil {
+					err = fmt.Errorf("field %s: %w", fieldType.Name, scanErr)
+				}
+				continue
+			}
+			// NULL leaves the field at its zero value, nil for pointers
+			if value == nil {
+				continue
+			}
+			val := reflect.ValueOf(value)
+
+			// Parse textual timestamps
+			if isTimeField(field.Type()) {
+				if text, ok := textValue(value); ok {
+					parsed, parseErr := parseTime(text, timeLayouts)
+					if parseErr != nil {
+						err = fmt.Errorf("field %s: %w", fieldType.Name, parseErr)
+						continue
 					}
-				} else {
+					val = reflect.ValueOf(parsed)
+				}
+			}
+
+			// Ensure the types are compatible
+			if field.Kind() == reflect.Ptr && val.Kind() != reflect.Ptr {
+				converted, ok := convertValue(val, field.Type().Elem())
+				if !ok {
 					err = errors.New("type mismatch for field: " + fieldType.Name)
+					continue
 				}
+				ptr := reflect.New(field.Type().Elem())
+				ptr.Elem().Set(converted)
+				field.Set(ptr)
+			} else if converted, ok := convertValue(val, field.Type()); ok {
+				field.Set(converted)
+			} else {
+				err = errors.New("type mismatch for field: " + fieldType.Name)
 			}
 		}
 	}
 	
 	return 
 }
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	defaultTimeLayouts = []string{time.RFC3339}
+)
+
+// convertValue converts val to t when no information is lost, such as widening an int64 into an int
+func convertValue(val reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if val.Type().AssignableTo(t) {
+		return val, true
+	}
+	if text, ok := textValue(val.Interface()); ok && t.Kind() == reflect.String {
+		return reflect.ValueOf(text).Convert(t), true
+	}
+	if val.Kind() == t.Kind() && val.Type().ConvertibleTo(t) {
+		return val.Convert(t), true
+	}
+	if !numconv.IsNumber(val.Kind()) || !numconv.IsNumber(t.Kind()) {
+		return reflect.Value{}, false
+	}
+	converted, err := numconv.Convert(val, t)
+	return converted, err == nil
+}
+
+// isTimeField reports whether t is time.Time or *time.Time
+func isTimeField(t reflect.Type) bool {
+	return t == timeType || (t.Kind() == reflect.Ptr && t.Elem() == timeType)
+}
+
+// textValue returns value as a string when it is a string or []byte
+func textValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+// parseTime parses text with the first layout that matches
+func parseTime(text string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as time with layouts %q", text, layouts)
+}
+
+// StructOption customizes how StructToMap reads a struct
+type StructOption func(*structOptions)
+
+type structOptions struct {
+	omitEmpty bool
+}
+
+// OmitEmpty makes StructToMap skip fields holding their zero value
+func OmitEmpty() StructOption {
+	return func(o *structOptions) {
+		o.omitEmpty = true
+	}
+}
+
+// StructToMap is the inverse of MapToStruct, it maps the exported fields of src by their
+// `db` tag, falling back to the field name. Fields tagged `db:"-"` are skipped, and so are
+// zero fields tagged with omitempty or every zero field when OmitEmpty is set.
+func StructToMap[T any](src *T, opts ...StructOption) map[string]any {
+	var options structOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if src == nil {
+		return nil
+	}
+	srcVal := reflect.ValueOf(src).Elem()
+	if srcVal.Kind() != reflect.Struct {
+		return nil
+	}
+	srcType := srcVal.Type()
+
+	result := make(map[string]any, srcVal.NumField())
+	for i := 0; i < srcVal.NumField(); i++ {
+		fieldType := srcType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		mapKey := fieldType.Name
+		omitEmpty := options.omitEmpty
+		if tag := fieldType.Tag.Get("db"); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				mapKey = parts[0]
+			}
+			for _, part := range parts[1:] {
+				if part == "omitempty" {
+					omitEmpty = true
+				}
+			}
+		}
+
+		field := srcVal.Field(i)
+		if omitEmpty && field.IsZero() {
+			continue
+		}
+		result[mapKey] = field.Interface()
+	}
+	return result
+}
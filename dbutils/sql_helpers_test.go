@@ -0,0 +1,296 @@
+package dbutils
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTime(t *testing.T) {
+	layouts := []string{time.RFC3339, "2006-01-02 15:04:05"}
+
+	parsed, err := parseTime("2024-03-01T10:30:00Z", layouts)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), parsed)
+
+	parsed, err = parseTime("2024-03-01 10:30:00", layouts)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), parsed)
+
+	_, err = parseTime("01/03/2024", layouts)
+	assert.ErrorContains(t, err, `cannot parse "01/03/2024"`)
+	assert.ErrorContains(t, err, "2006-01-02 15:04:05")
+}
+
+func TestTimeFieldHelpers(t *testing.T) {
+	assert.True(t, isTimeField(reflect.TypeOf(time.Time{})))
+	assert.True(t, isTimeField(reflect.TypeOf(&time.Time{})))
+	assert.False(t, isTimeField(reflect.TypeOf("")))
+
+	text, ok := textValue([]byte("2024-03-01"))
+	assert.True(t, ok)
+	assert.Equal(t, "2024-03-01", text)
+
+	_, ok = textValue(42)
+	assert.False(t, ok)
+}
+
+// fakeRows serves fixed rows and fails to scan the rows listed in scanErrs
+type fakeRows struct {
+	columns  []string
+	rows     [][]any
+	scanErrs map[int]error
+	current  int
+	closed   bool
+}
+
+func (f *fakeRows) Columns() ([]string, error) { return f.columns, nil }
+func (f *fakeRows) Err() error                 { return nil }
+func (f *fakeRows) Close() error               { f.closed = true; return nil }
+
+func (f *fakeRows) Next() bool {
+	f.current++
+	return f.current <= len(f.rows)
+}
+
+func (f *fakeRows) Scan(dest ...any) error {
+	idx := f.current - 1
+	if err := f.scanErrs[idx]; err != nil {
+		return err
+	}
+	for i, val := range f.rows[idx] {
+		*dest[i].(*any) = val
+	}
+	return nil
+}
+
+func TestMapSqlRowsWithHandler(t *testing.T) {
+	scanErr := errors.New("converting NULL to int is unsupported")
+	newRows := func() *fakeRows {
+		return &fakeRows{
+			columns:  []string{"id", "name"},
+			rows:     [][]any{{int64(1), "Alice"}, {nil, nil}, {int64(3), []byte("Carol")}},
+			scanErrs: map[int]error{1: scanErr},
+		}
+	}
+
+	t.Run("skip bad rows", func(t *testing.T) {
+		var failed []int
+		rows := newRows()
+		results, err := mapSqlRows(rows, func(rowIndex int, err error) bool {
+			failed = append(failed, rowIndex)
+			return true
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, failed)
+		assert.Equal(t, []map[string]interface{}{
+			{"id": int64(1), "name": "Alice"},
+			{"id": int64(3), "name": "Carol"},
+		}, results)
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("abort on bad row", func(t *testing.T) {
+		_, err := mapSqlRows(newRows(), func(rowIndex int, err error) bool { return false })
+		assert.Equal(t, scanErr, err)
+	})
+
+	t.Run("no handler aborts", func(t *testing.T) {
+		_, err := mapSqlRows(newRows(), nil)
+		assert.Equal(t, scanErr, err)
+	})
+}
+
+func TestMapToStruct(t *testing.T) {
+	type user struct {
+		ID       int64   `db:"id"`
+		Name     string  `db:"name,omitempty"`
+		Nickname *string `db:"nickname"`
+		Email    *string `db:"email"`
+		Age      int64
+		internal string
+	}
+
+	res, err := MapToStruct[user](map[string]interface{}{
+		"id":       int64(7),
+		"name":     "Alice",
+		"nickname": "ally",
+		"email":    nil,
+		"Age":      int64(25),
+		"internal": "ignored",
+	})
+	assert.NoError(t, err)
+	nickname := "ally"
+	assert.Equal(t, &user{ID: 7, Name: "Alice", Nickname: &nickname, Age: 25}, res)
+}
+
+func TestMapToStruct_TypeMismatch(t *testing.T) {
+	type user struct {
+		ID int64 `db:"id"`
+	}
+	_, err := MapToStruct[user](map[string]interface{}{"id": "seven"})
+	assert.EqualError(t, err, "type mismatch for field: ID")
+}
+
+func TestMapToStruct_NotStruct(t *testing.T) {
+	res, err := MapToStruct[int](map[string]interface{}{"id": 1})
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "dest must be a pointer to a struct")
+}
+
+func TestMapToStruct_TimeLayouts(t *testing.T) {
+	type event struct {
+		CreatedAt time.Time  `db:"created_at"`
+		UpdatedAt *time.Time `db:"updated_at"`
+	}
+
+	res, err := MapToStruct[event](map[string]interface{}{
+		"created_at": "2024-03-01 10:30:00",
+		"updated_at": []byte("2024-03-02T08:00:00Z"),
+	}, WithTimeLayouts(time.RFC3339, "2006-01-02 15:04:05"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), res.CreatedAt)
+	assert.Equal(t, time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), *res.UpdatedAt)
+
+	_, err = MapToStruct[event](map[string]interface{}{"created_at": "yesterday"}, WithTimeLayouts(time.RFC3339))
+	assert.ErrorContains(t, err, "field CreatedAt")
+}
+
+func TestMapToStruct_Conversions(t *testing.T) {
+	type order struct {
+		ID        int            `db:"id"`
+		Quantity  *uint16        `db:"quantity"`
+		Price     float64        `db:"price"`
+		Note      sql.NullString `db:"note"`
+		Discount  sql.NullInt64  `db:"discount"`
+		ShippedAt sql.NullTime   `db:"shipped_at"`
+		CreatedAt time.Time      `db:"created_at"`
+		Code      string         `db:"code"`
+		Comment   *string        `db:"comment"`
+		Count     int            `db:"count"`
+	}
+
+	created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	res, err := MapToStruct[order](map[string]interface{}{
+		"id":         int64(42),
+		"quantity":   int64(3),
+		"price":      int64(10),
+		"note":       "fragile",
+		"discount":   nil,
+		"shipped_at": created,
+		"created_at": []byte("2024-03-01T10:30:00Z"),
+		"code":       []byte("A1"),
+		"comment":    nil,
+		"count":      nil,
+	})
+	assert.NoError(t, err)
+	quantity := uint16(3)
+	assert.Equal(t, &order{
+		ID:        42,
+		Quantity:  &quantity,
+		Price:     10,
+		Note:      sql.NullString{String: "fragile", Valid: true},
+		ShippedAt: sql.NullTime{Time: created, Valid: true},
+		CreatedAt: created,
+		Code:      "A1",
+	}, res)
+}
+
+func TestMapToStruct_LossyConversion(t *testing.T) {
+	type row struct {
+		Small int8 `db:"small"`
+		Count uint `db:"count"`
+		Whole int  `db:"whole"`
+	}
+
+	_, err := MapToStruct[row](map[string]interface{}{"small": int64(300)})
+	assert.EqualError(t, err, "type mismatch for field: Small")
+
+	_, err = MapToStruct[row](map[string]interface{}{"count": int64(-1)})
+	assert.EqualError(t, err, "type mismatch for field: Count")
+
+	_, err = MapToStruct[row](map[string]interface{}{"whole": 2.5})
+	assert.EqualError(t, err, "type mismatch for field: Whole")
+}
+
+func TestConvertValueOutOfRange(t *testing.T) {
+	// Rounds up to 2^63, which does not fit an int64 whatever the platform does on the way back
+	_, ok := convertValue(reflect.ValueOf(int64(math.MaxInt64)), reflect.TypeOf(float64(0)))
+	assert.False(t, ok)
+
+	_, ok = convertValue(reflect.ValueOf(math.Ldexp(1, 63)), reflect.TypeOf(int64(0)))
+	assert.False(t, ok)
+
+	_, ok = convertValue(reflect.ValueOf(uint64(math.MaxUint64)), reflect.TypeOf(int64(0)))
+	assert.False(t, ok)
+
+	_, ok = convertValue(reflect.ValueOf(math.NaN()), reflect.TypeOf(int(0)))
+	assert.False(t, ok)
+
+	converted, ok := convertValue(reflect.ValueOf(int64(math.MinInt64)), reflect.TypeOf(float64(0)))
+	assert.True(t, ok)
+	assert.Equal(t, -math.Ldexp(1, 63), converted.Float())
+}
+
+func TestStructToMap(t *testing.T) {
+	type user struct {
+		ID       int64   `db:"id"`
+		Name     string  `db:"name"`
+		Email    *string `db:"email,omitempty"`
+		Password string  `db:"-"`
+		Age      int
+		internal string
+	}
+
+	src := &user{ID: 7, Name: "Alice", Password: "secret", internal: "x"}
+	assert.Equal(t, map[string]any{"id": int64(7), "name": "Alice", "Age": 0}, StructToMap(src))
+	assert.Equal(t, map[string]any{"id": int64(7), "name": "Alice"}, StructToMap(src, OmitEmpty()))
+
+	roundTrip, err := MapToStruct[user](StructToMap(src))
+	assert.NoError(t, err)
+	assert.Equal(t, &user{ID: 7, Name: "Alice"}, roundTrip)
+
+	assert.Nil(t, StructToMap[user](nil))
+}
+
+func TestStreamSqlRows(t *testing.T) {
+	newRows := func() *fakeRows {
+		return &fakeRows{
+			columns: []string{"id", "name"},
+			rows:    [][]any{{int64(1), []byte("Alice")}, {int64(2), "Bob"}, {int64(3), "Carol"}},
+		}
+	}
+
+	t.Run("visits every row", func(t *testing.T) {
+		var names []any
+		rows := newRows()
+		err := streamSqlRows(rows, nil, func(row map[string]interface{}) error {
+			names = append(names, row["name"])
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"Alice", "Bob", "Carol"}, names)
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("stops on callback error", func(t *testing.T) {
+		stop := errors.New("stop")
+		visited := 0
+		rows := newRows()
+		err := streamSqlRows(rows, nil, func(row map[string]interface{}) error {
+			visited++
+			if row["id"] == int64(2) {
+				return stop
+			}
+			return nil
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 2, visited)
+		assert.True(t, rows.closed)
+	})
+}
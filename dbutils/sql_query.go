@@ -0,0 +1,44 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+)
+
+// QueryAndMap runs query on db and maps the rows with MapSqlRows.
+// A query returning no rows gives an empty, non-nil slice.
+func QueryAndMap(ctx context.Context, db *sql.DB, query string, args ...any) ([]map[string]interface{}, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := MapSqlRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []map[string]interface{}{}
+	}
+	return results, nil
+}
+
+// QueryAndMapStructs runs query on db and scans the rows into T with ScanRowsToStructs.
+// A query returning no rows gives an empty, non-nil slice.
+func QueryAndMapStructs[T any](ctx context.Context, db *sql.DB, query string, args ...any) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := ScanRowsToStructs[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []T{}
+	}
+	return results, nil
+}
@@ -0,0 +1,62 @@
+package dbutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAndMap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users").WithArgs(25).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), []byte("Alice")))
+	results, err := QueryAndMap(context.Background(), db, "SELECT id, name FROM users WHERE age > ?", 25)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"id": int64(1), "name": "Alice"}}, results)
+
+	mock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	results, err = QueryAndMap(context.Background(), db, "SELECT id, name FROM users")
+	assert.NoError(t, err)
+	assert.NotNil(t, results)
+	assert.Empty(t, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryAndMapStructs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Alice").AddRow(int64(2), "Bob"))
+	users, err := QueryAndMapStructs[user](context.Background(), db, "SELECT id, name FROM users")
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}, users)
+
+	mock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	users, err = QueryAndMapStructs[user](context.Background(), db, "SELECT id, name FROM users")
+	assert.NoError(t, err)
+	assert.Equal(t, []user{}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryAndMap_Cancelled(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = QueryAndMap(ctx, db, "SELECT id FROM users")
+	assert.ErrorIs(t, err, context.Canceled)
+}
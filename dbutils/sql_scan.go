@@ -0,0 +1,114 @@
+package dbutils
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ScanRowsToStructs scans every row into a new T and closes rows.
+// Columns are matched case-insensitively against the `db` tag of each field, falling back
+// to the field name and its snake_case form. Columns without a matching field are skipped.
+// []byte values scanned into interface fields are converted to strings like MapSqlRows does.
+func ScanRowsToStructs[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+
+	var zero T
+	destType := reflect.TypeOf(zero)
+	if destType == nil || destType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot scan rows into %T, T must be a struct", zero)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fieldIdx := columnFields(destType, columns)
+
+	var results []T
+	for rows.Next() {
+		var item T
+		itemVal := reflect.ValueOf(&item).Elem()
+		targets := make([]any, len(columns))
+		for i, idx := range fieldIdx {
+			if idx < 0 {
+				targets[i] = new(any)
+			} else {
+				targets[i] = itemVal.Field(idx).Addr().Interface()
+			}
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		for _, idx := range fieldIdx {
+			if idx < 0 {
+				continue
+			}
+			field := itemVal.Field(idx)
+			if field.Kind() != reflect.Interface || field.IsNil() {
+				continue
+			}
+			if b, ok := field.Interface().([]byte); ok {
+				field.Set(reflect.ValueOf(string(b)))
+			}
+		}
+		results = append(results, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// columnFields returns the index of the field of destType matching each column, -1 when none does.
+// ScanRowsToStructs, ScanRowsChan and the insert id binding all match fields through it.
+func columnFields(destType reflect.Type, columns []string) []int {
+	fields := make(map[string]int)
+	fallbacks := make(map[string]int)
+	for i := 0; i < destType.NumField(); i++ {
+		fieldType := destType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		if tag := strings.Split(fieldType.Tag.Get("db"), ",")[0]; tag != "" {
+			fields[strings.ToLower(tag)] = i
+			continue
+		}
+		fallbacks[strings.ToLower(fieldType.Name)] = i
+		fallbacks[toSnakeCase(fieldType.Name)] = i
+	}
+
+	indexes := make([]int, len(columns))
+	for i, col := range columns {
+		key := strings.ToLower(col)
+		if idx, ok := fields[key]; ok {
+			indexes[i] = idx
+		} else if idx, ok := fallbacks[key]; ok {
+			indexes[i] = idx
+		} else {
+			indexes[i] = -1
+		}
+	}
+	return indexes
+}
+
+// toSnakeCase converts a Go identifier such as UserID to user_id
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
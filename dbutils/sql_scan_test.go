@@ -0,0 +1,58 @@
+package dbutils
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanRowsToStructs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	type user struct {
+		ID        int64  `db:"ID"`
+		UserName  string
+		CreatedBy string
+		Note      any
+		Active    bool `db:"is_active"`
+	}
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "user_name", "createdby", "note", "is_active", "unused"}).
+			AddRow(int64(1), []byte("alice"), "admin", []byte("vip"), true, "x").
+			AddRow(int64(2), "bob", "admin", nil, false, "y"),
+	)
+	rows, err := db.Query("SELECT * FROM users")
+	assert.NoError(t, err)
+
+	users, err := ScanRowsToStructs[user](rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []user{
+		{ID: 1, UserName: "alice", CreatedBy: "admin", Note: "vip", Active: true},
+		{ID: 2, UserName: "bob", CreatedBy: "admin"},
+	}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRowsToStructs_NotStruct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows, err := db.Query("SELECT id FROM users")
+	assert.NoError(t, err)
+
+	_, err = ScanRowsToStructs[int](rows)
+	assert.EqualError(t, err, "cannot scan rows into int, T must be a struct")
+}
+
+func TestToSnakeCase(t *testing.T) {
+	assert.Equal(t, "user_id", toSnakeCase("UserID"))
+	assert.Equal(t, "created_at", toSnakeCase("CreatedAt"))
+	assert.Equal(t, "http_server", toSnakeCase("HTTPServer"))
+	assert.Equal(t, "name", toSnakeCase("Name"))
+}
@@ -0,0 +1,93 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// ScanRowsChan scans rows lazily onto a channel so large result sets can be
+// processed without materializing them in memory.
+// Struct types are populated by matching columns the same way ScanRowsToStructs does,
+// any other type, including sql.Scanner implementations such as sql.NullString and time.Time,
+// is scanned directly from a single column.
+// Both channels are closed once the rows are exhausted, an error occurs or ctx is cancelled.
+// At most one error is sent on the error channel.
+func ScanRowsChan[T any](ctx context.Context, rows *sql.Rows) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+
+			var item T
+			targets, err := scanTargets(reflect.ValueOf(&item).Elem(), columns)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := rows.Scan(targets...); err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case items <- item:
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return items, errs
+}
+
+// scanTargets returns the pointers rows.Scan should write each column into.
+// Columns without a matching struct field are discarded.
+func scanTargets(dest reflect.Value, columns []string) ([]any, error) {
+	if !scansFields(dest) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("cannot scan %d columns into %s", len(columns), dest.Type())
+		}
+		return []any{dest.Addr().Interface()}, nil
+	}
+
+	targets := make([]any, len(columns))
+	for i, idx := range columnFields(dest.Type(), columns) {
+		if idx < 0 {
+			targets[i] = new(any)
+		} else {
+			targets[i] = dest.Field(idx).Addr().Interface()
+		}
+	}
+	return targets, nil
+}
+
+// scansFields reports whether dest is a struct populated field by field rather than scanned as a whole
+func scansFields(dest reflect.Value) bool {
+	if dest.Kind() != reflect.Struct || dest.Type() == timeType {
+		return false
+	}
+	_, isScanner := dest.Addr().Interface().(sql.Scanner)
+	return !isScanner
+}
@@ -0,0 +1,181 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type exportUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	Age  int
+}
+
+func TestScanRowsChan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name", "Age", "ignored"}).
+			AddRow(1, "Alice", 25, "x").
+			AddRow(2, "Bob", 30, "y"),
+	)
+
+	rows, err := db.Query("SELECT id, name, Age, ignored FROM users")
+	assert.NoError(t, err)
+
+	items, errs := ScanRowsChan[exportUser](context.Background(), rows)
+	var users []exportUser
+	for user := range items {
+		users = append(users, user)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []exportUser{{ID: 1, Name: "Alice", Age: 25}, {ID: 2, Name: "Bob", Age: 30}}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRowsChan_SingleColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Alice").AddRow("Bob"))
+
+	rows, err := db.Query("SELECT name FROM users")
+	assert.NoError(t, err)
+
+	items, errs := ScanRowsChan[string](context.Background(), rows)
+	var names []string
+	for name := range items {
+		names = append(names, name)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"Alice", "Bob"}, names)
+}
+
+func TestScanRowsChan_ScannerAndTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	t.Run("sql.Scanner", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"nickname"}).AddRow("ally").AddRow(nil))
+		rows, err := db.Query("SELECT nickname FROM users")
+		assert.NoError(t, err)
+
+		items, errs := ScanRowsChan[sql.NullString](context.Background(), rows)
+		var nicknames []sql.NullString
+		for nickname := range items {
+			nicknames = append(nicknames, nickname)
+		}
+		assert.NoError(t, <-errs)
+		assert.Equal(t, []sql.NullString{{String: "ally", Valid: true}, {}}, nicknames)
+	})
+
+	t.Run("time.Time", func(t *testing.T) {
+		created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+		rows, err := db.Query("SELECT created_at FROM users")
+		assert.NoError(t, err)
+
+		items, errs := ScanRowsChan[time.Time](context.Background(), rows)
+		var times []time.Time
+		for item := range items {
+			times = append(times, item)
+		}
+		assert.NoError(t, <-errs)
+		assert.Equal(t, []time.Time{created}, times)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRowsChan_RowError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rowErr := errors.New("connection reset")
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "Alice").
+			AddRow(2, "Bob").
+			RowError(1, rowErr),
+	)
+
+	rows, err := db.Query("SELECT id, name FROM users")
+	assert.NoError(t, err)
+
+	items, errs := ScanRowsChan[exportUser](context.Background(), rows)
+	count := 0
+	for range items {
+		count++
+	}
+	assert.Equal(t, 1, count)
+	assert.ErrorIs(t, <-errs, rowErr)
+}
+
+func TestScanRowsChan_Cancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alice").AddRow(2, "Bob"),
+	)
+
+	rows, err := db.Query("SELECT id, name FROM users")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	items, errs := ScanRowsChan[exportUser](ctx, rows)
+	cancel()
+
+	count := 0
+	for range items {
+		count++
+	}
+	assert.Equal(t, 0, count)
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestScanRowsChan_MatchesScanRowsToStructs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	type user struct {
+		ID        int64 `db:"ID"`
+		UserName  string
+		CreatedBy string
+	}
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_name", "createdby"}).AddRow(int64(1), "alice", "admin")
+	}
+
+	mock.ExpectQuery("SELECT").WillReturnRows(newRows())
+	rows, err := db.Query("SELECT * FROM users")
+	assert.NoError(t, err)
+	scanned, err := ScanRowsToStructs[user](rows)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(newRows())
+	rows, err = db.Query("SELECT * FROM users")
+	assert.NoError(t, err)
+	items, errs := ScanRowsChan[user](context.Background(), rows)
+	var streamed []user
+	for item := range items {
+		streamed = append(streamed, item)
+	}
+	assert.NoError(t, <-errs)
+
+	assert.Equal(t, []user{{ID: 1, UserName: "alice", CreatedBy: "admin"}}, streamed)
+	assert.Equal(t, scanned, streamed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+
+	"github.com/mahadev-k/go-utils/retry"
 )
 
 type TxnFn[T any] func(ctx context.Context, txn *sql.Tx, processingReq *T) error
@@ -13,21 +16,61 @@ type StatefulTxnFn[T any, R any] func(ctx context.Context, txn *sql.Tx, processi
 // For dependent writes you may need to add the dependent data to processReq and proceed to the next function call
 type SqlTxnExec[T any, R any] struct {
 	db               *sql.DB
+	txnOpts          *sql.TxOptions
 	txn              *sql.Tx
 	txnFns         []TxnFn[T]
 	statefulTxnFns []StatefulTxnFn[T, R]
+	// txnNames and statefulNames hold the step names, empty for unnamed steps
+	txnNames         []string
+	statefulNames    []string
 	processingReq    *T
 	processedRes     *R
 	ctx              context.Context
 	err              error
+	done             bool
+	wrapStepErrors   bool
+	strictResponse   *responseTracker
+	retryPolicy      *retry.RetryPolicy
+	dialect          SavepointDialect
+	stepSavepoints   bool
+	failedSteps      []*StepError
+	onCommit         []func()
+	onRollback       []func(err error)
+	readOnly         bool
+	committed        bool
+	rolledBack       bool
+}
+
+// StepError reports which step of a SqlTxnExec failed.
+// Index is the 1-based position of the step in execution order, Exec steps run before StatefulExec steps.
+// Name is the name given through ExecNamed or StatefulExecNamed, empty for unnamed steps.
+type StepError struct {
+	Index int
+	Name  string
+	Err   error
 }
 
+func (e *StepError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("step %q: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("step %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the original step error so errors.Is and errors.As work
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// NewSqlTxnExec begins a transaction on db for the steps added to the executor.
+// If the transaction cannot be started the steps are ignored and Commit returns the error.
 func NewSqlTxnExec[T any, R any](ctx context.Context, db *sql.DB, opts *sql.TxOptions, processingReq *T) *SqlTxnExec[T, R] {
 	tx, err := db.BeginTx(ctx, opts)
 	var processedRes R
 	return &SqlTxnExec[T, R]{
 		ctx:           ctx,
 		db:            db,
+		txnOpts:       opts,
 		txn:           tx,
 		processingReq: processingReq,
 		processedRes:  &processedRes,
@@ -35,39 +78,176 @@ func NewSqlTxnExec[T any, R any](ctx context.Context, db *sql.DB, opts *sql.TxOp
 	}
 }
 
+// NewSqlTxnExecE is like NewSqlTxnExec but returns the error of starting the transaction right away
+func NewSqlTxnExecE[T any, R any](ctx context.Context, db *sql.DB, opts *sql.TxOptions, processingReq *T) (*SqlTxnExec[T, R], error) {
+	s := NewSqlTxnExec[T, R](ctx, db, opts, processingReq)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s, nil
+}
+
 func (s *SqlTxnExec[T, R]) Exec(txnFn TxnFn[T]) *SqlTxnExec[T, R] {
+	if s.err != nil {
+		return s
+	}
+	if s.readOnly {
+		s.err = ErrReadOnlyWrite
+		return s
+	}
 	s.txnFns = append(s.txnFns, txnFn)
+	s.txnNames = append(s.txnNames, "")
+	return s
+}
+
+// ExecNamed adds a step like Exec, a failure of the step is wrapped in a StepError carrying name
+func (s *SqlTxnExec[T, R]) ExecNamed(name string, txnFn TxnFn[T]) *SqlTxnExec[T, R] {
+	s.Exec(txnFn)
+	if s.err == nil {
+		s.txnNames[len(s.txnNames)-1] = name
+	}
 	return s
 }
 
 func (s *SqlTxnExec[T, R]) StatefulExec(statefulTxnFn StatefulTxnFn[T, R]) *SqlTxnExec[T, R] {
+	if s.err != nil {
+		return s
+	}
+	if s.readOnly {
+		s.err = ErrReadOnlyWrite
+		return s
+	}
+	return s.addStatefulStep("", statefulTxnFn)
+}
+
+// StatefulExecNamed adds a step like StatefulExec, a failure of the step is wrapped in a StepError carrying name
+func (s *SqlTxnExec[T, R]) StatefulExecNamed(name string, statefulTxnFn StatefulTxnFn[T, R]) *SqlTxnExec[T, R] {
+	if s.err != nil {
+		return s
+	}
+	if s.readOnly {
+		s.err = ErrReadOnlyWrite
+		return s
+	}
+	return s.addStatefulStep(name, statefulTxnFn)
+}
+
+// addStatefulStep appends a step that runs after the Exec steps
+func (s *SqlTxnExec[T, R]) addStatefulStep(name string, statefulTxnFn StatefulTxnFn[T, R]) *SqlTxnExec[T, R] {
 	s.statefulTxnFns = append(s.statefulTxnFns, statefulTxnFn)
+	s.statefulNames = append(s.statefulNames, name)
 	return s
 }
 
-func (s *SqlTxnExec[T, R]) Commit() (err error) {
+// WithStepErrors wraps the error of a failing step in a StepError carrying its position.
+// It is off by default so step errors keep their identity for == comparisons.
+func (s *SqlTxnExec[T, R]) WithStepErrors() *SqlTxnExec[T, R] {
+	s.wrapStepErrors = true
+	return s
+}
+
+// Commit runs the registered steps and commits the transaction, rolling back if any step fails.
+// With WithRetry, retryable failures run all steps again on a new transaction.
+// Calling Commit on an executor that already finished returns sql.ErrTxDone.
+func (s *SqlTxnExec[T, R]) Commit() error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	defer func() { s.done = true }()
+	if s.err != nil {
+		// The transaction was never started or the chain is invalid
+		if s.txn == nil {
+			return s.err
+		}
+		rollbackErr := s.txn.Rollback()
+		s.rolledBack = rollbackErr == nil
+		s.runHooks(s.err)
+		return errors.Join(s.err, rollbackErr)
+	}
+
+	var err error
+	if s.retryPolicy != nil {
+		err = s.commitWithRetry()
+	} else {
+		err = s.runAndCommit()
+	}
+	if err == nil && len(s.failedSteps) > 0 {
+		err = &PartialCommitError{Steps: s.failedSteps}
+	}
+	s.runHooks(err)
+	return err
+}
+
+// runAndCommit runs every step on the current transaction and commits it, or rolls it back on failure
+func (s *SqlTxnExec[T, R]) runAndCommit() (err error) {
 	defer func() {
 		if p := recover(); p != nil {
 			s.txn.Rollback()
 			panic(p)
 		} else if err != nil {
-			err = errors.Join(err, s.txn.Rollback())
+			rollbackErr := s.txn.Rollback()
+			s.rolledBack = rollbackErr == nil
+			err = errors.Join(err, rollbackErr)
 		} else {
 			err = errors.Join(err, s.txn.Commit())
+			s.committed = err == nil
 		}
 		return
 	}()
 
-	for _, writeFn := range s.txnFns {
+	for i, writeFn := range s.txnFns {
 		if err = writeFn(s.ctx, s.txn, s.processingReq); err != nil {
+			err = s.stepError(i, s.txnNames[i], err)
 			return
 		}
 	}
 
-	for _, statefulWriteFn := range s.statefulTxnFns {
-		if err = statefulWriteFn(s.ctx, s.txn, s.processingReq, s.processedRes); err != nil {
+	if s.strictResponse != nil {
+		s.strictResponse.snapshot(s.processedRes)
+	}
+	for i, statefulWriteFn := range s.statefulTxnFns {
+		if s.stepSavepoints {
+			err = s.runInSavepoint(len(s.txnFns)+i, s.statefulNames[i], statefulWriteFn)
+		} else {
+			err = statefulWriteFn(s.ctx, s.txn, s.processingReq, s.processedRes)
+		}
+		if err != nil {
+			err = s.stepError(len(s.txnFns)+i, s.statefulNames[i], err)
 			return
 		}
+		if s.strictResponse != nil {
+			s.strictResponse.observe(i+1, s.processedRes)
+		}
 	}
 	return
 }
+
+// Rollback aborts the transaction without running any of the registered steps.
+// It is a no-op once the executor has been committed or rolled back.
+func (s *SqlTxnExec[T, R]) Rollback() error {
+	if s.done || s.txn == nil {
+		return nil
+	}
+	s.done = true
+	if err := s.txn.Rollback(); err != nil {
+		return err
+	}
+	s.rolledBack = true
+	s.runHooks(nil)
+	return nil
+}
+
+// Close releases the transaction if it was never committed, so it is safe to defer
+// right after building the executor.
+func (s *SqlTxnExec[T, R]) Close() error {
+	return s.Rollback()
+}
+
+// stepError wraps err for the step at the 0-based position idx when the step is named
+// or step errors are enabled
+func (s *SqlTxnExec[T, R]) stepError(idx int, name string, err error) error {
+	if name == "" && !s.wrapStepErrors {
+		return err
+	}
+	return &StepError{Index: idx + 1, Name: name, Err: err}
+}
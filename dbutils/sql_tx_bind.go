@@ -0,0 +1,70 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// InsertFn runs a write inside the transaction and returns its sql.Result
+type InsertFn[T any] func(ctx context.Context, txn *sql.Tx, processingReq *T) (sql.Result, error)
+
+// BindInsertID returns a stateful step that runs fn and stores its LastInsertId
+// into the field of the response named fieldName. The field is matched by its
+// `db` tag first and then by its Go name, and must be an integer or a pointer to one.
+func BindInsertID[T any, R any](fieldName string, fn InsertFn[T]) StatefulTxnFn[T, R] {
+	return func(ctx context.Context, txn *sql.Tx, processingReq *T, processedRes *R) error {
+		res, err := fn(ctx, txn, processingReq)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		return setIntField(reflect.ValueOf(processedRes).Elem(), fieldName, id)
+	}
+}
+
+// setIntField sets the integer field matching name on the struct dest
+func setIntField(dest reflect.Value, name string, value int64) error {
+	if dest.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind %s on non struct type %s", name, dest.Type())
+	}
+
+	field, ok := fieldByTagOrName(dest, name)
+	if !ok {
+		return fmt.Errorf("field %s not found on %s", name, dest.Type())
+	}
+
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		field.Set(ptr)
+		field = ptr.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.OverflowInt(value) {
+			return fmt.Errorf("insert id %d overflows field %s", value, name)
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value < 0 || field.OverflowUint(uint64(value)) {
+			return fmt.Errorf("insert id %d overflows field %s", value, name)
+		}
+		field.SetUint(uint64(value))
+	default:
+		return fmt.Errorf("field %s is %s, expected an integer", name, field.Type())
+	}
+	return nil
+}
+
+// fieldByTagOrName finds the exported field a column called name would be scanned into
+func fieldByTagOrName(dest reflect.Value, name string) (reflect.Value, bool) {
+	if idx := columnFields(dest.Type(), []string{name})[0]; idx >= 0 {
+		return dest.Field(idx), true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,40 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownStep is returned by BuildChain when a step name is missing from the registry
+var ErrUnknownStep = errors.New("unknown step")
+
+// BuildChain builds a SqlTxnExec from a registry of named stateful steps.
+// Steps are added in the order given by names, which lets the transaction flow
+// be driven by config or feature flags instead of code.
+// Names are validated before the transaction is started, so an unknown name
+// never leaves an open transaction behind.
+func BuildChain[T any, R any](
+	ctx context.Context,
+	db *sql.DB,
+	opts *sql.TxOptions,
+	processingReq *T,
+	registry map[string]StatefulTxnFn[T, R],
+	names []string,
+) (*SqlTxnExec[T, R], error) {
+	steps := make([]StatefulTxnFn[T, R], 0, len(names))
+	for _, name := range names {
+		step, ok := registry[name]
+		if !ok || step == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownStep, name)
+		}
+		steps = append(steps, step)
+	}
+
+	exec := NewSqlTxnExec[T, R](ctx, db, opts, processingReq)
+	for _, step := range steps {
+		exec.StatefulExec(step)
+	}
+	return exec, nil
+}
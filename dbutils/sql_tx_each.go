@@ -0,0 +1,23 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type EachTxnFn[Item any, R any] func(ctx context.Context, txn *sql.Tx, item Item, processedRes *R) error
+
+// StatefulExecEach registers a single stateful step on s that runs fn for every item, in order,
+// inside the executor's transaction. The first failing item stops the step and rolls back the
+// whole transaction, its error is prefixed with the 0-based item index.
+func StatefulExecEach[T, R, Item any](s *SqlTxnExec[T, R], items []Item, fn EachTxnFn[Item, R]) *SqlTxnExec[T, R] {
+	return s.StatefulExec(func(ctx context.Context, txn *sql.Tx, processingReq *T, processedRes *R) error {
+		for i, item := range items {
+			if err := fn(ctx, txn, item, processedRes); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
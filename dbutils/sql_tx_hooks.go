@@ -0,0 +1,31 @@
+package dbutils
+
+// OnCommit registers fn to run after the transaction is committed, for example to
+// publish outbox events or invalidate caches. It does not run if the commit fails.
+// Hooks run in registration order.
+func (s *SqlTxnExec[T, R]) OnCommit(fn func()) *SqlTxnExec[T, R] {
+	s.onCommit = append(s.onCommit, fn)
+	return s
+}
+
+// OnRollback registers fn to run after the transaction is rolled back.
+// err is the error that caused the rollback, nil when Rollback or Close was called.
+// Hooks run in registration order.
+func (s *SqlTxnExec[T, R]) OnRollback(fn func(err error)) *SqlTxnExec[T, R] {
+	s.onRollback = append(s.onRollback, fn)
+	return s
+}
+
+// runHooks calls the hooks matching how the transaction ended
+func (s *SqlTxnExec[T, R]) runHooks(err error) {
+	switch {
+	case s.committed:
+		for _, fn := range s.onCommit {
+			fn()
+		}
+	case s.rolledBack:
+		for _, fn := range s.onRollback {
+			fn(err)
+		}
+	}
+}
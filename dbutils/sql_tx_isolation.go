@@ -0,0 +1,57 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrReadOnlyWrite is returned by Commit when a write step was added to a read-only executor
+var ErrReadOnlyWrite = errors.New("write step added to a read-only transaction")
+
+// NewSerializableTxnExec starts a transaction with the serializable isolation level
+func NewSerializableTxnExec[T any, R any](ctx context.Context, db *sql.DB, processingReq *T) *SqlTxnExec[T, R] {
+	return NewSqlTxnExec[T, R](ctx, db, &sql.TxOptions{Isolation: sql.LevelSerializable}, processingReq)
+}
+
+// NewRepeatableReadTxnExec starts a transaction with the repeatable read isolation level
+func NewRepeatableReadTxnExec[T any, R any](ctx context.Context, db *sql.DB, processingReq *T) *SqlTxnExec[T, R] {
+	return NewSqlTxnExec[T, R](ctx, db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, processingReq)
+}
+
+// NewReadCommittedTxnExec starts a transaction with the read committed isolation level
+func NewReadCommittedTxnExec[T any, R any](ctx context.Context, db *sql.DB, processingReq *T) *SqlTxnExec[T, R] {
+	return NewSqlTxnExec[T, R](ctx, db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, processingReq)
+}
+
+// WithReadOnly restarts the transaction as read-only, keeping its isolation level.
+// Only Query steps may be used afterwards, adding an Exec or StatefulExec step makes
+// Commit fail with ErrReadOnlyWrite. It must be called before any step is added.
+func (s *SqlTxnExec[T, R]) WithReadOnly() *SqlTxnExec[T, R] {
+	if s.err != nil || s.done || s.readOnly {
+		return s
+	}
+	s.readOnly = true
+	if len(s.txnFns) > 0 || len(s.statefulTxnFns) > 0 {
+		s.err = ErrReadOnlyWrite
+		return s
+	}
+
+	opts := sql.TxOptions{}
+	if s.txnOpts != nil {
+		opts = *s.txnOpts
+	}
+	if opts.ReadOnly {
+		return s
+	}
+	opts.ReadOnly = true
+	s.txnOpts = &opts
+
+	// No step ran yet, so the transaction can be replaced
+	if err := s.txn.Rollback(); err != nil {
+		s.txn, s.err = nil, err
+		return s
+	}
+	s.txn, s.err = s.db.BeginTx(s.ctx, s.txnOpts)
+	return s
+}
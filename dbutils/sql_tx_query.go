@@ -0,0 +1,28 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+)
+
+// QueryFn is a step that reads inside the transaction, typically a SELECT ... FOR UPDATE
+// whose result is stored in processedRes for the write steps that follow
+type QueryFn[T any, R any] func(ctx context.Context, txn *sql.Tx, processingReq *T, processedRes *R) error
+
+// Query adds a read step. It runs in order with the StatefulExec steps and a failure
+// rolls back the transaction like any other step. Query steps are allowed in read-only mode.
+func (s *SqlTxnExec[T, R]) Query(queryFn QueryFn[T, R]) *SqlTxnExec[T, R] {
+	if s.err != nil {
+		return s
+	}
+	return s.addStatefulStep("", StatefulTxnFn[T, R](queryFn))
+}
+
+// MapRowsInTx runs query on txn and maps the returned rows with MapSqlRows
+func MapRowsInTx(ctx context.Context, txn *sql.Tx, query string, args ...any) ([]map[string]interface{}, error) {
+	rows, err := txn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return MapSqlRows(rows)
+}
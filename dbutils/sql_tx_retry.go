@@ -0,0 +1,75 @@
+package dbutils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
+)
+
+// WithRetry makes Commit run the whole chain again on a new transaction when it fails
+// with an error isRetryable accepts, such as a deadlock or serialization failure.
+// At most maxAttempts transactions are tried. A nil isRetryable retries every error.
+// Every attempt starts from a zero response, steps should not rely on changes they
+// made to the request in a failed attempt.
+func (s *SqlTxnExec[T, R]) WithRetry(maxAttempts int, isRetryable func(error) bool) *SqlTxnExec[T, R] {
+	if s.retryPolicy == nil {
+		s.retryPolicy = &retry.RetryPolicy{}
+	}
+	s.retryPolicy.MaxAttempts = maxAttempts
+	s.retryPolicy.IsRetryable = isRetryable
+	return s
+}
+
+// WithRetryBackoff waits between retries, doubling the delay from base after every attempt
+func (s *SqlTxnExec[T, R]) WithRetryBackoff(base time.Duration) *SqlTxnExec[T, R] {
+	if s.retryPolicy == nil {
+		s.retryPolicy = &retry.RetryPolicy{MaxAttempts: 1}
+	}
+	s.retryPolicy.Backoff = retry.Exponential(base)
+	return s
+}
+
+// commitWithRetry runs the chain under the retry policy, beginning a new transaction for every retry.
+// The returned error wraps the error of the last attempt.
+func (s *SqlTxnExec[T, R]) commitWithRetry() error {
+	attempts := 0
+	var lastErr error
+	_, err := retry.Retry(s.ctx, *s.retryPolicy, func() (struct{}, error) {
+		attempts++
+		if attempts > 1 {
+			if lastErr = s.resetAttempt(); lastErr != nil {
+				return struct{}{}, lastErr
+			}
+		}
+		lastErr = s.runAndCommit()
+		return struct{}{}, lastErr
+	})
+	if err == nil {
+		return nil
+	}
+	if err != lastErr {
+		// The context ended while waiting for the next attempt
+		err = errors.Join(err, lastErr)
+	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", attempts, err)
+}
+
+// resetAttempt begins a new transaction and clears the state left by the previous attempt
+func (s *SqlTxnExec[T, R]) resetAttempt() error {
+	txn, err := s.db.BeginTx(s.ctx, s.txnOpts)
+	if err != nil {
+		return err
+	}
+	s.txn = txn
+	s.rolledBack = false
+
+	var zero R
+	*s.processedRes = zero
+	s.failedSteps = nil
+	if s.strictResponse != nil {
+		s.strictResponse = &responseTracker{writers: map[string]int{}}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package dbutils
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mahadev-k/go-utils/clone"
+)
+
+// SavepointDialect builds the savepoint statements for a database.
+// An empty statement is skipped, for databases that have no equivalent.
+type SavepointDialect interface {
+	Savepoint(name string) string
+	RollbackTo(name string) string
+	Release(name string) string
+}
+
+type standardDialect struct{}
+
+func (standardDialect) Savepoint(name string) string  { return "SAVEPOINT " + name }
+func (standardDialect) RollbackTo(name string) string { return "ROLLBACK TO SAVEPOINT " + name }
+func (standardDialect) Release(name string) string    { return "RELEASE SAVEPOINT " + name }
+
+type sqlServerDialect struct{}
+
+func (sqlServerDialect) Savepoint(name string) string  { return "SAVE TRANSACTION " + name }
+func (sqlServerDialect) RollbackTo(name string) string { return "ROLLBACK TRANSACTION " + name }
+func (sqlServerDialect) Release(name string) string    { return "" }
+
+var (
+	// StandardSavepoints uses the SQL standard syntax understood by PostgreSQL, MySQL and SQLite
+	StandardSavepoints SavepointDialect = standardDialect{}
+	// SQLServerSavepoints uses SAVE TRANSACTION, SQL Server has no way to release a savepoint
+	SQLServerSavepoints SavepointDialect = sqlServerDialect{}
+)
+
+var savepointNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PartialCommitError is returned by Commit in step savepoint mode when the transaction
+// was committed but some steps failed and were rolled back to their savepoint
+type PartialCommitError struct {
+	Steps []*StepError
+}
+
+func (e *PartialCommitError) Error() string {
+	return fmt.Sprintf("transaction committed with %d failed steps: %v", len(e.Steps), e.Unwrap())
+}
+
+// Unwrap returns the failed step errors so errors.Is and errors.As reach them
+func (e *PartialCommitError) Unwrap() []error {
+	errs := make([]error, len(e.Steps))
+	for i, step := range e.Steps {
+		errs[i] = step
+	}
+	return errs
+}
+
+// WithDialect sets the savepoint syntax of the database, StandardSavepoints by default
+func (s *SqlTxnExec[T, R]) WithDialect(dialect SavepointDialect) *SqlTxnExec[T, R] {
+	s.dialect = dialect
+	return s
+}
+
+// WithStepSavepoints runs every StatefulExec step inside its own savepoint.
+// A failing step is rolled back to its savepoint, its changes to the response are
+// undone and the remaining steps still run. The transaction is committed and Commit
+// returns a *PartialCommitError listing the failed steps.
+// The response is backed up with clone.DeepCopy before every step, a response that
+// cannot be copied, such as one holding a channel, fails the transaction.
+func (s *SqlTxnExec[T, R]) WithStepSavepoints() *SqlTxnExec[T, R] {
+	s.stepSavepoints = true
+	return s
+}
+
+// Savepoint marks a point of the transaction that RollbackTo can return to
+func (s *SqlTxnExec[T, R]) Savepoint(name string) error {
+	return s.execSavepoint(name, s.savepointDialect().Savepoint)
+}
+
+// RollbackTo undoes everything done in the transaction since the savepoint name
+func (s *SqlTxnExec[T, R]) RollbackTo(name string) error {
+	return s.execSavepoint(name, s.savepointDialect().RollbackTo)
+}
+
+// ReleaseSavepoint forgets the savepoint name, keeping the work done since it
+func (s *SqlTxnExec[T, R]) ReleaseSavepoint(name string) error {
+	return s.execSavepoint(name, s.savepointDialect().Release)
+}
+
+func (s *SqlTxnExec[T, R]) savepointDialect() SavepointDialect {
+	if s.dialect == nil {
+		return StandardSavepoints
+	}
+	return s.dialect
+}
+
+// execSavepoint runs the statement built for name, names are restricted to identifiers
+// since they cannot be passed as query arguments
+func (s *SqlTxnExec[T, R]) execSavepoint(name string, statement func(name string) string) error {
+	if !savepointNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid savepoint name %q", name)
+	}
+	if s.txn == nil {
+		return fmt.Errorf("savepoint %s: no transaction", name)
+	}
+	query := statement(name)
+	if query == "" {
+		return nil
+	}
+	_, err := s.txn.ExecContext(s.ctx, query)
+	return err
+}
+
+// runInSavepoint runs the step at the 0-based position idx inside a savepoint.
+// A failure of the step is recorded and rolled back, only savepoint errors and a response
+// that cannot be backed up are returned.
+func (s *SqlTxnExec[T, R]) runInSavepoint(idx int, stepName string, fn StatefulTxnFn[T, R]) error {
+	name := fmt.Sprintf("step_%d", idx+1)
+	// Without a backup a failed step could not be undone in the response, so the step does not run
+	backup, err := clone.DeepCopy(*s.processedRes)
+	if err != nil {
+		return fmt.Errorf("backing up the response for %s: %w", name, err)
+	}
+	if err := s.Savepoint(name); err != nil {
+		return err
+	}
+
+	if stepErr := fn(s.ctx, s.txn, s.processingReq, s.processedRes); stepErr != nil {
+		if err := s.RollbackTo(name); err != nil {
+			return fmt.Errorf("rolling back %s: %w", name, err)
+		}
+		*s.processedRes = backup
+		s.failedSteps = append(s.failedSteps, &StepError{Index: idx + 1, Name: stepName, Err: stepErr})
+	}
+	return s.ReleaseSavepoint(name)
+}
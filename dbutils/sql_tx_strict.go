@@ -0,0 +1,94 @@
+package dbutils
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mahadev-k/go-utils/clone"
+)
+
+// FieldOverwrite reports a response field written by more than one stateful step.
+// Steps are identified by their 1-based position among the StatefulExec steps.
+type FieldOverwrite struct {
+	Field      string
+	FirstStep  int
+	SecondStep int
+}
+
+func (f FieldOverwrite) String() string {
+	return fmt.Sprintf("field %s written by step %d was overwritten by step %d", f.Field, f.FirstStep, f.SecondStep)
+}
+
+// WithStrictResponse tracks which stateful step writes each exported field of the response
+// and records a FieldOverwrite whenever a later step changes a field an earlier step already
+// wrote. The recorded overwrites are available from ResponseOverwrites after Commit.
+// Tracking snapshots the response after every step, so it is meant for tests and debugging.
+func (s *SqlTxnExec[T, R]) WithStrictResponse() *SqlTxnExec[T, R] {
+	s.strictResponse = &responseTracker{writers: map[string]int{}}
+	return s
+}
+
+// ResponseOverwrites returns the overwrites detected in strict response mode
+func (s *SqlTxnExec[T, R]) ResponseOverwrites() []FieldOverwrite {
+	if s.strictResponse == nil {
+		return nil
+	}
+	return s.strictResponse.overwrites
+}
+
+type responseTracker struct {
+	last       any
+	writers    map[string]int
+	overwrites []FieldOverwrite
+}
+
+// snapshot records the response state before the first step runs
+func (r *responseTracker) snapshot(res any) {
+	r.last = copyResponse(res)
+}
+
+// observe compares the response after step with the previous snapshot
+func (r *responseTracker) observe(step int, res any) {
+	current := copyResponse(res)
+	if r.last != nil && current != nil {
+		prevVal := reflect.ValueOf(r.last)
+		currVal := reflect.ValueOf(current)
+		for _, field := range changedFields(prevVal, currVal) {
+			if first, ok := r.writers[field]; ok && first != step {
+				r.overwrites = append(r.overwrites, FieldOverwrite{Field: field, FirstStep: first, SecondStep: step})
+			}
+			r.writers[field] = step
+		}
+	}
+	r.last = current
+}
+
+// copyResponse deep copies the value behind the response pointer, nil if it cannot be copied
+func copyResponse(res any) any {
+	copied, err := clone.DeepCopy(reflect.ValueOf(res).Elem().Interface())
+	if err != nil {
+		return nil
+	}
+	return copied
+}
+
+// changedFields lists the exported fields that differ between prev and curr
+func changedFields(prev, curr reflect.Value) []string {
+	if prev.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(prev.Interface(), curr.Interface()) {
+			return []string{prev.Type().String()}
+		}
+		return nil
+	}
+
+	var changed []string
+	for i := 0; i < prev.NumField(); i++ {
+		if !prev.Type().Field(i).IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(prev.Field(i).Interface(), curr.Field(i).Interface()) {
+			changed = append(changed, prev.Type().Field(i).Name)
+		}
+	}
+	return changed
+}
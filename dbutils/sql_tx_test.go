@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -246,3 +247,765 @@ func TestSqlWriteExec_StatefulRollbackOnFailure(t *testing.T) {
 	// Verify mock expectations
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestBuildChain_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	orderReq := &OrderRequest{
+		CustomerName: "John Doe",
+		TotalAmount:  100.50,
+	}
+	orderReq.Payment.Method = "Credit Card"
+
+	registry := map[string]StatefulTxnFn[OrderRequest, ProcessedResponse]{
+		"order":   insertOrder,
+		"payment": insertPayment,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").
+		WithArgs("John Doe", 100.50).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO payments").
+		WithArgs(1, 100.50, "Credit Card").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	exec, err := BuildChain(ctx, db, nil, orderReq, registry, []string{"order", "payment"})
+	assert.NoError(t, err)
+	assert.NoError(t, exec.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBuildChain_UnknownStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	registry := map[string]StatefulTxnFn[OrderRequest, ProcessedResponse]{
+		"order": insertOrder,
+	}
+
+	exec, err := BuildChain(context.Background(), db, nil, &OrderRequest{}, registry, []string{"order", "refund"})
+	assert.Nil(t, exec)
+	assert.ErrorIs(t, err, ErrUnknownStep)
+	assert.Contains(t, err.Error(), "refund")
+	// No transaction should have been started
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, nil).
+		Exec(insertUser)
+
+	assert.NoError(t, exec.Rollback())
+	// Rollback and Close are idempotent
+	assert.NoError(t, exec.Rollback())
+	assert.NoError(t, exec.Close())
+	// Commit after rollback is rejected without touching the database
+	assert.ErrorIs(t, exec.Commit(), sql.ErrTxDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_CloseAfterCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = func() error {
+		exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, nil).
+			Exec(insertUser)
+		defer exec.Close()
+		return exec.Commit()
+	}()
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_CloseWithoutCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	func() {
+		exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, nil).
+			Exec(insertUser)
+		defer exec.Close()
+	}()
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type BoundResponse struct {
+	OrderID   int64 `db:"order_id"`
+	PaymentID *int
+	Note      string
+}
+
+func execOrderInsert(ctx context.Context, txn *sql.Tx, orderReq *OrderRequest) (sql.Result, error) {
+	return txn.ExecContext(ctx, "INSERT INTO orders (customer_name, total_amount) VALUES (?, ?)",
+		orderReq.CustomerName,
+		orderReq.TotalAmount,
+	)
+}
+
+func execPaymentInsert(ctx context.Context, txn *sql.Tx, orderReq *OrderRequest) (sql.Result, error) {
+	return txn.ExecContext(ctx, "INSERT INTO payments (amount) VALUES (?)", orderReq.TotalAmount)
+}
+
+func TestBindInsertID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").
+		WithArgs("John Doe", 100.50).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO payments").
+		WithArgs(100.50).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectCommit()
+
+	exec := NewSqlTxnExec[OrderRequest, BoundResponse](context.Background(), db, nil, &OrderRequest{CustomerName: "John Doe", TotalAmount: 100.50}).
+		StatefulExec(BindInsertID[OrderRequest, BoundResponse]("order_id", execOrderInsert)).
+		StatefulExec(BindInsertID[OrderRequest, BoundResponse]("PaymentID", execPaymentInsert))
+	assert.NoError(t, exec.Commit())
+
+	assert.Equal(t, int64(7), exec.processedRes.OrderID)
+	assert.Equal(t, 9, *exec.processedRes.PaymentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBindInsertID_InvalidField(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	for _, field := range []string{"Missing", "Note"} {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		err = NewSqlTxnExec[OrderRequest, BoundResponse](context.Background(), db, nil, &OrderRequest{}).
+			StatefulExec(BindInsertID[OrderRequest, BoundResponse](field, execOrderInsert)).
+			Commit()
+		assert.ErrorContains(t, err, field)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithStepErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	forced := errors.New("forced error during stateful insert")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	err = NewSqlTxnExec[OrderRequest, ProcessedResponse](context.Background(), db, nil, &OrderRequest{CustomerName: "Jane Doe"}).
+		WithStepErrors().
+		StatefulExec(successfulStatefulInsert).
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, orderReq *OrderRequest, processedRes *ProcessedResponse) error {
+			return forced
+		}).
+		Commit()
+
+	var stepErr *StepError
+	assert.True(t, errors.As(err, &stepErr))
+	assert.Equal(t, 2, stepErr.Index)
+	assert.ErrorIs(t, err, forced)
+	assert.EqualError(t, err, "step 2: forced error during stateful insert")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type BulkResponse struct {
+	OrderIDs []int64
+}
+
+func insertEachOrder(ctx context.Context, txn *sql.Tx, orderReq OrderRequest, processedRes *BulkResponse) error {
+	res, err := txn.ExecContext(ctx, "INSERT INTO orders (customer_name, total_amount) VALUES (?, ?)",
+		orderReq.CustomerName,
+		orderReq.TotalAmount,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	processedRes.OrderIDs = append(processedRes.OrderIDs, id)
+	return nil
+}
+
+func TestStatefulExecEach(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	orders := []OrderRequest{
+		{CustomerName: "Alice", TotalAmount: 10},
+		{CustomerName: "Bob", TotalAmount: 20},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WithArgs("Alice", 10.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WithArgs("Bob", 20.0).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	exec := NewSqlTxnExec[struct{}, BulkResponse](context.Background(), db, nil, nil)
+	err = StatefulExecEach(exec, orders, insertEachOrder).Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, exec.processedRes.OrderIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatefulExecEach_RollbackOnItemFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	orders := []OrderRequest{
+		{CustomerName: "Alice", TotalAmount: 10},
+		{CustomerName: "Bob", TotalAmount: 20},
+		{CustomerName: "Carol", TotalAmount: 30},
+	}
+	insertErr := errors.New("insert failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WithArgs("Alice", 10.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WithArgs("Bob", 20.0).WillReturnError(insertErr)
+	mock.ExpectRollback()
+
+	exec := NewSqlTxnExec[struct{}, BulkResponse](context.Background(), db, nil, nil)
+	err = StatefulExecEach(exec, orders, insertEachOrder).Commit()
+	assert.ErrorIs(t, err, insertErr)
+	assert.EqualError(t, err, "item 1: insert failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithStrictResponse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	exec := NewSqlTxnExec[OrderRequest, ProcessedResponse](context.Background(), db, nil, &OrderRequest{}).
+		WithStrictResponse().
+		StatefulExec(insertOrder).
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, orderReq *OrderRequest, processedRes *ProcessedResponse) error {
+			return nil
+		}).
+		StatefulExec(insertOrder)
+	assert.NoError(t, exec.Commit())
+
+	assert.Equal(t, []FieldOverwrite{{Field: "OrderID", FirstStep: 1, SecondStep: 3}}, exec.ResponseOverwrites())
+	assert.Equal(t, "field OrderID written by step 1 was overwritten by step 3", exec.ResponseOverwrites()[0].String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithoutStrictResponse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	exec := NewSqlTxnExec[OrderRequest, ProcessedResponse](context.Background(), db, nil, &OrderRequest{}).
+		StatefulExec(insertOrder).
+		StatefulExec(insertOrder)
+	assert.NoError(t, exec.Commit())
+	assert.Nil(t, exec.ResponseOverwrites())
+}
+
+var errDeadlock = errors.New("deadlock detected")
+
+func isDeadlock(err error) bool {
+	return errors.Is(err, errDeadlock)
+}
+
+func TestSqlTxnExec_WithRetry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnError(errDeadlock)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	calls := 0
+	res := 0
+	exec := NewSqlTxnExec[struct{}, int](context.Background(), db, nil, &struct{}{}).
+		WithRetry(3, isDeadlock).
+		WithRetryBackoff(time.Millisecond).
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, processedRes *int) error {
+			calls++
+			*processedRes++
+			if err := updateInventory(ctx, txn, req); err != nil {
+				return err
+			}
+			res = *processedRes
+			return nil
+		})
+
+	assert.NoError(t, exec.Commit())
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, res, "response is reset between attempts")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithRetry_Exhausted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnError(errDeadlock)
+		mock.ExpectRollback()
+	}
+
+	err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+		WithRetry(2, isDeadlock).
+		Exec(updateInventory).
+		Commit()
+
+	assert.ErrorIs(t, err, errDeadlock)
+	assert.ErrorContains(t, err, "after 2 attempts")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithRetry_NotRetryable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+		WithRetry(3, isDeadlock).
+		Exec(insertUser).
+		Commit()
+
+	assert.ErrorContains(t, err, "insert failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_Savepoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT before_inventory").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT before_inventory").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{})
+	defer exec.Close()
+
+	assert.NoError(t, exec.Savepoint("before_inventory"))
+	assert.NoError(t, exec.RollbackTo("before_inventory"))
+	assert.ErrorContains(t, exec.Savepoint("x; DROP TABLE users"), "invalid savepoint name")
+	assert.NoError(t, exec.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithStepSavepoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT step_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT step_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT step_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnError(errDeadlock)
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT step_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT step_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	type response struct {
+		UserID    int
+		Inventory int
+	}
+	exec := NewSqlTxnExec[struct{}, response](context.Background(), db, nil, &struct{}{}).
+		WithStepSavepoints().
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, res *response) error {
+			res.UserID = 1
+			return insertUser(ctx, txn, req)
+		}).
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, res *response) error {
+			res.Inventory = 10
+			return updateInventory(ctx, txn, req)
+		})
+
+	err = exec.Commit()
+	var partialErr *PartialCommitError
+	assert.ErrorAs(t, err, &partialErr)
+	assert.Len(t, partialErr.Steps, 1)
+	assert.Equal(t, 2, partialErr.Steps[0].Index)
+	assert.ErrorIs(t, err, errDeadlock)
+	assert.Equal(t, response{UserID: 1}, *exec.processedRes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithStepSavepointsUncopyableResponse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT step_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT step_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	type response struct {
+		Done chan struct{}
+	}
+	ran := false
+	err = NewSqlTxnExec[struct{}, response](context.Background(), db, nil, &struct{}{}).
+		WithStepSavepoints().
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, res *response) error {
+			res.Done = make(chan struct{})
+			return nil
+		}).
+		StatefulExecNamed("notify", func(ctx context.Context, txn *sql.Tx, req *struct{}, res *response) error {
+			ran = true
+			return nil
+		}).
+		Commit()
+
+	assert.ErrorContains(t, err, `step "notify": backing up the response for step_2`)
+	assert.False(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_SQLServerSavepoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVE TRANSACTION step_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+		WithDialect(SQLServerSavepoints).
+		WithStepSavepoints().
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, res *struct{}) error {
+			return insertUser(ctx, txn, req)
+		}).
+		Commit()
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_BeginError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	errBegin := errors.New("connection refused")
+	mock.ExpectBegin().WillReturnError(errBegin)
+
+	called := false
+	exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+		Exec(func(ctx context.Context, txn *sql.Tx, req *struct{}) error {
+			called = true
+			return nil
+		})
+
+	assert.ErrorIs(t, exec.Commit(), errBegin)
+	assert.False(t, called)
+	assert.NoError(t, exec.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewSqlTxnExecE(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	errBegin := errors.New("connection refused")
+	mock.ExpectBegin().WillReturnError(errBegin)
+	exec, err := NewSqlTxnExecE[struct{}, struct{}](context.Background(), db, nil, &struct{}{})
+	assert.ErrorIs(t, err, errBegin)
+	assert.Nil(t, exec)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	exec, err = NewSqlTxnExecE[struct{}, struct{}](context.Background(), db, nil, &struct{}{})
+	assert.NoError(t, err)
+	assert.NoError(t, exec.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_Query(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT stock FROM inventory").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(int64(25)))
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	exec := NewSqlTxnExec[struct{}, int64](context.Background(), db, nil, &struct{}{}).
+		Query(func(ctx context.Context, txn *sql.Tx, req *struct{}, stock *int64) error {
+			rows, err := MapRowsInTx(ctx, txn, "SELECT stock FROM inventory WHERE product_id = ? FOR UPDATE", 1)
+			if err != nil {
+				return err
+			}
+			*stock = rows[0]["stock"].(int64)
+			return nil
+		}).
+		StatefulExec(func(ctx context.Context, txn *sql.Tx, req *struct{}, stock *int64) error {
+			if *stock < 10 {
+				return errors.New("not enough stock")
+			}
+			return updateInventory(ctx, txn, req)
+		})
+
+	assert.NoError(t, exec.Commit())
+	assert.Equal(t, int64(25), *exec.processedRes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_QueryRollbackOnFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT stock FROM inventory").WillReturnError(errDeadlock)
+	mock.ExpectRollback()
+
+	err = NewSqlTxnExec[struct{}, int64](context.Background(), db, nil, &struct{}{}).
+		Query(func(ctx context.Context, txn *sql.Tx, req *struct{}, stock *int64) error {
+			_, err := MapRowsInTx(ctx, txn, "SELECT stock FROM inventory WHERE product_id = ? FOR UPDATE", 1)
+			return err
+		}).
+		Commit()
+
+	assert.ErrorIs(t, err, errDeadlock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_Hooks(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		var calls []string
+		err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+			Exec(insertUser).
+			OnCommit(func() { calls = append(calls, "first") }).
+			OnCommit(func() { calls = append(calls, "second") }).
+			OnRollback(func(err error) { calls = append(calls, "rollback") }).
+			Commit()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnError(errDeadlock)
+		mock.ExpectRollback()
+
+		committed := false
+		var rollbackErr error
+		err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+			Exec(insertUser).
+			OnCommit(func() { committed = true }).
+			OnRollback(func(err error) { rollbackErr = err }).
+			Commit()
+
+		assert.ErrorIs(t, err, errDeadlock)
+		assert.False(t, committed)
+		assert.ErrorIs(t, rollbackErr, errDeadlock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed commit runs no hooks", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errDeadlock)
+
+		hooks := 0
+		err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+			OnCommit(func() { hooks++ }).
+			OnRollback(func(err error) { hooks++ }).
+			Commit()
+
+		assert.ErrorIs(t, err, errDeadlock)
+		assert.Equal(t, 0, hooks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("explicit rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		rollbacks := 0
+		exec := NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+			OnRollback(func(err error) {
+				assert.NoError(t, err)
+				rollbacks++
+			})
+
+		assert.NoError(t, exec.Rollback())
+		assert.NoError(t, exec.Close())
+		assert.Equal(t, 1, rollbacks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestNewSerializableTxnExec(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	exec := NewSerializableTxnExec[struct{}, struct{}](context.Background(), db, &struct{}{})
+	assert.Equal(t, sql.LevelSerializable, exec.txnOpts.Isolation)
+	assert.NoError(t, exec.Exec(updateInventory).Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSqlTxnExec_WithReadOnly(t *testing.T) {
+	t.Run("allows queries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT stock FROM inventory").WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(int64(5)))
+		mock.ExpectCommit()
+
+		exec := NewReadCommittedTxnExec[struct{}, int64](context.Background(), db, &struct{}{}).
+			WithReadOnly().
+			Query(func(ctx context.Context, txn *sql.Tx, req *struct{}, stock *int64) error {
+				rows, err := MapRowsInTx(ctx, txn, "SELECT stock FROM inventory")
+				if err != nil {
+					return err
+				}
+				*stock = rows[0]["stock"].(int64)
+				return nil
+			})
+
+		assert.Equal(t, sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: true}, *exec.txnOpts)
+		assert.NoError(t, exec.Commit())
+		assert.Equal(t, int64(5), *exec.processedRes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects writes", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		var rollbackErr error
+		err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+			WithReadOnly().
+			OnRollback(func(err error) { rollbackErr = err }).
+			Exec(updateInventory).
+			Commit()
+
+		assert.ErrorIs(t, err, ErrReadOnlyWrite)
+		assert.ErrorIs(t, rollbackErr, ErrReadOnlyWrite)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSqlTxnExec_NamedSteps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WithArgs("Alice", 25).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE inventory").WithArgs(10, 1).WillReturnError(errDeadlock)
+	mock.ExpectRollback()
+
+	err = NewSqlTxnExec[struct{}, struct{}](context.Background(), db, nil, &struct{}{}).
+		ExecNamed("insert user", insertUser).
+		StatefulExecNamed("update inventory", func(ctx context.Context, txn *sql.Tx, req *struct{}, res *struct{}) error {
+			return updateInventory(ctx, txn, req)
+		}).
+		Commit()
+
+	assert.EqualError(t, err, `step "update inventory": deadlock detected`)
+	var stepErr *StepError
+	assert.ErrorAs(t, err, &stepErr)
+	assert.Equal(t, 2, stepErr.Index)
+	assert.Equal(t, "update inventory", stepErr.Name)
+	assert.ErrorIs(t, err, errDeadlock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
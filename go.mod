@@ -2,7 +2,12 @@ module github.com/mahadev-k/go-utils
 
 go 1.23.2
 
-require github.com/stretchr/testify v1.9.0
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/stretchr/testify v1.9.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.2
@@ -0,0 +1,25 @@
+package goctx
+
+import "context"
+
+// Bind turns fn and its argument into a RunFn, e.g. Bind(fetchUser, userID)
+func Bind[A, T any](fn func(A) (T, error), arg A) RunFn[T] {
+	return func() (T, error) {
+		return fn(arg)
+	}
+}
+
+// Bind2 turns fn and its two arguments into a RunFn
+func Bind2[A, B, T any](fn func(A, B) (T, error), a A, b B) RunFn[T] {
+	return func() (T, error) {
+		return fn(a, b)
+	}
+}
+
+// BindCtx turns a context aware fn and its argument into a RunFn that calls fn with ctx,
+// e.g. BindCtx(ctx, fetchUser, userID) for func fetchUser(ctx context.Context, id string) (User, error)
+func BindCtx[A, T any](ctx context.Context, fn func(context.Context, A) (T, error), arg A) RunFn[T] {
+	return func() (T, error) {
+		return fn(ctx, arg)
+	}
+}
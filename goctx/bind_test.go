@@ -0,0 +1,48 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind(t *testing.T) {
+	ctx := NewTaskContext(context.Background())
+	repeat := func(s string, n int) (string, error) {
+		res := ""
+		for i := 0; i < n; i++ {
+			res += s
+		}
+		return res, nil
+	}
+	lookup := func(ctx context.Context, id int) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return "user-" + strconv.Itoa(id), nil
+	}
+
+	number := Run(ctx, Bind(strconv.Atoi, "7"))
+	assert.Equal(t, 7, number)
+
+	results, err := RunParallel(ctx,
+		Bind2(repeat, "ab", 2),
+		BindCtx(context.Background(), lookup, 42),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abab", "user-42"}, results)
+}
+
+func TestBindCtxCancelled(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx := NewTaskContext(context.Background())
+	_ = Run(ctx, BindCtx(cancelled, func(ctx context.Context, id int) (int, error) {
+		return 0, ctx.Err()
+	}, 1))
+	assert.True(t, errors.Is(ctx.Err(), context.Canceled))
+}
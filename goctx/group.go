@@ -0,0 +1,58 @@
+package goctx
+
+// WaitMode selects what TaskContext.Wait reports
+type WaitMode int
+
+const (
+	// WaitAllErrors makes Wait return every collected error joined together
+	WaitAllErrors WaitMode = iota
+	// WaitFirstError makes Wait return only the first error returned by a Go function, like errgroup
+	WaitFirstError
+)
+
+// WithWaitMode sets the error reporting mode of Wait
+func (c *TaskContext) WithWaitMode(mode WaitMode) *TaskContext {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waitMode = mode
+	return c
+}
+
+// Go runs fn in a new goroutine and records its error on the context.
+// Together with Wait it mirrors golang.org/x/sync/errgroup:
+//
+//	g := new(errgroup.Group)   ->  ctx := goctx.NewTaskContext(parent)
+//	g.Go(fn)                   ->  ctx.Go(fn)
+//	err := g.Wait()            ->  err := ctx.Wait()
+//
+// Unlike errgroup.WithContext a failure does not cancel the parent context.
+func (c *TaskContext) Go(fn func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if err := c.getPanicPolicy().Call(fn); err != nil {
+			c.mu.Lock()
+			if c.firstGoErr == nil {
+				c.firstGoErr = err
+			}
+			c.mu.Unlock()
+			c.AddError(err)
+		}
+	}()
+}
+
+// Wait blocks until every function started with Go has returned.
+// It returns all errors collected on the context joined together, or only the
+// first error of a Go function when the context is in WaitFirstError mode.
+func (c *TaskContext) Wait() error {
+	c.wg.Wait()
+
+	c.mu.RLock()
+	mode, firstErr := c.waitMode, c.firstGoErr
+	c.mu.RUnlock()
+
+	if mode == WaitFirstError {
+		return firstErr
+	}
+	return c.Errors()
+}
@@ -0,0 +1,55 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskContext_GoWait(t *testing.T) {
+	t.Run("waits for all goroutines", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		done := atomic.Int32{}
+		for i := 0; i < 5; i++ {
+			ctx.Go(func() error {
+				time.Sleep(5 * time.Millisecond)
+				done.Add(1)
+				return nil
+			})
+		}
+		assert.NoError(t, ctx.Wait())
+		assert.Equal(t, int32(5), done.Load())
+	})
+
+	t.Run("joins all errors by default", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		err1 := errors.New("error 1")
+		err2 := errors.New("error 2")
+		ctx.Go(func() error { return err1 })
+		ctx.Go(func() error { return nil })
+		ctx.Go(func() error { return err2 })
+
+		err := ctx.Wait()
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+	})
+
+	t.Run("first error mode", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background()).WithWaitMode(WaitFirstError)
+		first := errors.New("first")
+		second := errors.New("second")
+		ctx.Go(func() error { return first })
+		ctx.Go(func() error {
+			time.Sleep(20 * time.Millisecond)
+			return second
+		})
+
+		assert.Equal(t, first, ctx.Wait())
+		// Every error is still collected on the context
+		assert.ErrorIs(t, ctx.Errors(), second)
+	})
+}
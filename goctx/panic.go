@@ -0,0 +1,74 @@
+package goctx
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy governs what happens when a task running in a worker goroutine panics
+type PanicPolicy int
+
+const (
+	// PanicPropagate lets the panic crash the process
+	PanicPropagate PanicPolicy = iota
+	// PanicRecoverAsError recovers the panic and records it as a *PanicError
+	// holding the panic value and the stack trace of the panicking goroutine
+	PanicRecoverAsError
+)
+
+// PanicError is the error recorded for a recovered panic
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap exposes the panic value when it is an error, e.g. panic(err)
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Call runs fn applying the policy to any panic it raises
+func (p PanicPolicy) Call(fn func() error) (err error) {
+	if p != PanicRecoverAsError {
+		return fn()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// WithPanicPolicy sets how panics in RunParallel, RunParallelWithLimit and Go tasks are handled.
+// Contexts created with NewTaskContext use PanicRecoverAsError, so a panicking task is reported
+// as an error of the batch instead of crashing the process.
+func (c *TaskContext) WithPanicPolicy(policy PanicPolicy) *TaskContext {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.panicPolicy = policy
+	return c
+}
+
+func (c *TaskContext) getPanicPolicy() PanicPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.panicPolicy
+}
+
+// runTask runs a parallel task under the context's panic policy
+func runTask[T any](ctx *TaskContext, fn RunFn[T]) (result T, err error) {
+	err = ctx.getPanicPolicy().Call(func() error {
+		var fnErr error
+		result, fnErr = fn()
+		return fnErr
+	})
+	return result, err
+}
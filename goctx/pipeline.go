@@ -0,0 +1,23 @@
+package goctx
+
+// RunPipeline2 runs fa and feeds its result to the stage built by fb, like calling Run
+// twice but without building or running the second stage once an error is recorded on ctx.
+// It returns the result of the last stage, or the zero value of B when a stage failed.
+func RunPipeline2[A, B any](ctx *TaskContext, fa RunFn[A], fb func(A) RunFn[B]) B {
+	var zero B
+	a := Run(ctx, fa)
+	if ctx.Err() != nil {
+		return zero
+	}
+	return Run(ctx, fb(a))
+}
+
+// RunPipeline3 runs three dependent stages like RunPipeline2
+func RunPipeline3[A, B, C any](ctx *TaskContext, fa RunFn[A], fb func(A) RunFn[B], fc func(B) RunFn[C]) C {
+	var zero C
+	b := RunPipeline2(ctx, fa, fb)
+	if ctx.Err() != nil {
+		return zero
+	}
+	return Run(ctx, fc(b))
+}
@@ -0,0 +1,50 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPipeline(t *testing.T) {
+	calculateShipping := func(weight int) RunFn[float64] {
+		return func() (float64, error) { return float64(weight) * 1.5, nil }
+	}
+	generateLabel := func(cost float64) RunFn[string] {
+		return func() (string, error) { return fmt.Sprintf("LABEL-%.1f", cost), nil }
+	}
+
+	t.Run("two stages", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		cost := RunPipeline2(ctx, func() (int, error) { return 4, nil }, calculateShipping)
+		assert.NoError(t, ctx.Err())
+		assert.Equal(t, 6.0, cost)
+	})
+
+	t.Run("three stages", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		label := RunPipeline3(ctx, func() (int, error) { return 4, nil }, calculateShipping, generateLabel)
+		assert.NoError(t, ctx.Err())
+		assert.Equal(t, "LABEL-6.0", label)
+	})
+
+	t.Run("short circuits on error", func(t *testing.T) {
+		errInvalid := errors.New("invalid address")
+		ctx := NewTaskContext(context.Background())
+		built := false
+		label := RunPipeline3(ctx,
+			func() (int, error) { return 0, errInvalid },
+			func(weight int) RunFn[float64] {
+				built = true
+				return calculateShipping(weight)
+			},
+			generateLabel,
+		)
+		assert.Equal(t, "", label)
+		assert.False(t, built)
+		assert.Equal(t, errInvalid, ctx.Err())
+	})
+}
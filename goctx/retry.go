@@ -0,0 +1,31 @@
+package goctx
+
+import (
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
+)
+
+// RunWithRetry behaves like Run but calls fn up to attempts times until it succeeds,
+// waiting backoff(n) before the n-th retry. A nil backoff retries immediately.
+// Only the error of the last attempt is recorded on ctx. Cancelling the parent
+// context stops the retries at once and records the context error.
+func RunWithRetry[T any](ctx *TaskContext, attempts int, backoff func(attempt int) time.Duration, fn RunFnCtx[T]) T {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero
+	}
+
+	policy := retry.RetryPolicy{MaxAttempts: attempts, Backoff: backoff}
+	result, err := retry.Retry(ctx.Context, policy, func() (T, error) {
+		if err := cancelErr(ctx.Context); err != nil {
+			return zero, err
+		}
+		return fn(ctx.Context)
+	})
+	if err != nil {
+		ctx.WithError(err)
+		return zero
+	}
+	return result
+}
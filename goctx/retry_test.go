@@ -0,0 +1,69 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithRetry(t *testing.T) {
+	errTransient := errors.New("transient")
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		calls := 0
+		result := RunWithRetry(ctx, 3, retry.Constant(time.Millisecond), func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errTransient
+			}
+			return 42, nil
+		})
+		assert.Equal(t, 42, result)
+		assert.Equal(t, 3, calls)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("records only the final error", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		calls := 0
+		result := RunWithRetry(ctx, 2, nil, func(ctx context.Context) (int, error) {
+			calls++
+			return 0, errTransient
+		})
+		assert.Equal(t, 0, result)
+		assert.Equal(t, 2, calls)
+		assert.ErrorIs(t, ctx.Err(), errTransient)
+		assert.Len(t, ctx.multiErr, 1)
+	})
+
+	t.Run("cancelled parent stops retrying", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		ctx := NewTaskContext(parent)
+		calls := 0
+		start := time.Now()
+		RunWithRetry(ctx, 5, retry.Constant(time.Second), func(ctx context.Context) (int, error) {
+			calls++
+			cancel()
+			return 0, errTransient
+		})
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.ErrorIs(t, ctx.Errors(), context.Canceled)
+	})
+
+	t.Run("skips when context already failed", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		ctx.WithError(errTransient)
+		called := false
+		RunWithRetry(ctx, 3, nil, func(ctx context.Context) (int, error) {
+			called = true
+			return 1, nil
+		})
+		assert.False(t, called)
+	})
+}
@@ -0,0 +1,66 @@
+package goctx
+
+import "sync"
+
+// Run2 runs two tasks of different result types concurrently and waits for both.
+// Failures are recorded on ctx as *TaskError like RunParallel, the failed result is left at its zero value.
+func Run2[A, B any](ctx *TaskContext, fa RunFn[A], fb RunFn[B]) (A, B, error) {
+	var a A
+	var b B
+	if err := ctx.Err(); err != nil {
+		return a, b, err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a = runTyped(ctx, 1, fa)
+	}()
+	go func() {
+		defer wg.Done()
+		b = runTyped(ctx, 2, fb)
+	}()
+	wg.Wait()
+
+	return a, b, ctx.Errors()
+}
+
+// Run3 runs three tasks of different result types concurrently like Run2
+func Run3[A, B, C any](ctx *TaskContext, fa RunFn[A], fb RunFn[B], fc RunFn[C]) (A, B, C, error) {
+	var a A
+	var b B
+	var c C
+	if err := ctx.Err(); err != nil {
+		return a, b, c, err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		a = runTyped(ctx, 1, fa)
+	}()
+	go func() {
+		defer wg.Done()
+		b = runTyped(ctx, 2, fb)
+	}()
+	go func() {
+		defer wg.Done()
+		c = runTyped(ctx, 3, fc)
+	}()
+	wg.Wait()
+
+	return a, b, c, ctx.Errors()
+}
+
+// runTyped runs the task at the 1-based position index, recording its error on ctx
+func runTyped[T any](ctx *TaskContext, index int, fn RunFn[T]) T {
+	result, err := runTask(ctx, fn)
+	if err != nil {
+		ctx.AddError(&TaskError{Index: index, Err: err})
+		var zero T
+		return zero
+	}
+	return result
+}
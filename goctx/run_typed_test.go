@@ -0,0 +1,65 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun2(t *testing.T) {
+	ctx := NewTaskContext(context.Background())
+	address, cost, err := Run2(ctx,
+		func() (string, error) { return "221B Baker Street", nil },
+		func() (float64, error) { return 12.5, nil },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "221B Baker Street", address)
+	assert.Equal(t, 12.5, cost)
+}
+
+func TestRun3(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		valid, cost, label, err := Run3(ctx,
+			func() (bool, error) { return true, nil },
+			func() (float64, error) { return 12.5, nil },
+			func() (string, error) { return "TRACK-1", nil },
+		)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.Equal(t, 12.5, cost)
+		assert.Equal(t, "TRACK-1", label)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		errInvalid := errors.New("invalid address")
+		ctx := NewTaskContext(context.Background())
+		valid, cost, _, err := Run3(ctx,
+			func() (bool, error) { return true, errInvalid },
+			func() (float64, error) { return 12.5, nil },
+			func() (string, error) { return "TRACK-1", nil },
+		)
+		assert.ErrorIs(t, err, errInvalid)
+		assert.False(t, valid)
+		assert.Equal(t, 12.5, cost)
+
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 1)
+		assert.Equal(t, 1, taskErrs[0].Index)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		cancel()
+		called := false
+		_, _, _, err := Run3(NewTaskContext(parent),
+			func() (bool, error) { called = true; return true, nil },
+			func() (float64, error) { called = true; return 0, nil },
+			func() (string, error) { called = true; return "", nil },
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+	})
+}
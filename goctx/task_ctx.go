@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 )
 
@@ -14,6 +15,15 @@ type TaskContext struct {
 	mu       sync.RWMutex
 	err      error
 	multiErr []error
+	cacheMu  sync.Mutex
+	cache    map[string]*cacheEntry
+
+	wg         sync.WaitGroup
+	waitMode   WaitMode
+	firstGoErr error
+
+	panicPolicy PanicPolicy
+	values      map[any]any
 }
 
 // NewTaskContext returns a new TaskContext that wraps the parent context.
@@ -21,7 +31,7 @@ func NewTaskContext(parent context.Context) *TaskContext {
 	if parent == nil {
 		panic("cannot create context from nil parent")
 	}
-	return &TaskContext{Context: parent}
+	return &TaskContext{Context: parent, panicPolicy: PanicRecoverAsError}
 }
 
 // WithError sets the first error on the context and joins with existing errors
@@ -58,24 +68,38 @@ func (c *TaskContext) AddError(err error) {
 	}
 }
 
-// Err returns the first error stored in the context
+// Err reports why tasks on this context should stop, in order of precedence:
+//  1. the cancellation or deadline error of the underlying context
+//  2. the error recorded on this context
+//  3. the error recorded on the closest parent TaskContext that has one
 func (c *TaskContext) Err() error {
-	// First check parent context's error (timeout, cancel, etc)
-	if err := c.Context.Err(); err != nil {
+	if err := cancelErr(c.Context); err != nil {
 		return err
 	}
+	return c.recordedErr()
+}
 
-	// Then check parent's custom errors if it's a TaskContext
-	if tc, ok := c.Context.(*TaskContext); ok {
-		if err := tc.err; err != nil {
-			return err
-		}
+// recordedErr returns the error recorded on c, falling back to its parent TaskContexts
+func (c *TaskContext) recordedErr() error {
+	c.mu.RLock()
+	err := c.err
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if parent, ok := c.Context.(*TaskContext); ok {
+		return parent.recordedErr()
 	}
+	return nil
+}
 
-	// Finally check our own custom error
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.err
+// cancelErr returns the cancellation error of ctx, looking through TaskContexts
+// so their recorded errors are not mistaken for a cancellation
+func cancelErr(ctx context.Context) error {
+	if tc, ok := ctx.(*TaskContext); ok {
+		return cancelErr(tc.Context)
+	}
+	return ctx.Err()
 }
 
 // Errors returns all collected errors joined together
@@ -89,6 +113,73 @@ func (c *TaskContext) Errors() error {
 	return errors.Join(c.multiErr...)
 }
 
+// ClearErrors forgets every error recorded on the context so it can be reused for an
+// independent phase. A cancelled or expired embedded context stays done, Err keeps
+// reporting it.
+func (c *TaskContext) ClearErrors() *TaskContext {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = nil
+	c.multiErr = nil
+	c.firstGoErr = nil
+	return c
+}
+
+// MergeTaskErrors joins the errors collected by several task contexts.
+// Each context's errors stay grouped together as a single joined error, so
+// errors.Is, errors.As and TaskError lookups still reach every original error.
+func MergeTaskErrors(ctxs ...*TaskContext) error {
+	groups := make([]error, 0, len(ctxs))
+	for _, ctx := range ctxs {
+		if ctx == nil {
+			continue
+		}
+		groups = append(groups, ctx.Errors())
+	}
+	return errors.Join(groups...)
+}
+
+// TaskError is the error recorded for a single failed task of a parallel run
+// Index is the 1-based position of the task in the call, Name is set for RunParallelNamed tasks
+type TaskError struct {
+	Index int
+	Name  string
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	task := fmt.Sprintf("task %d", e.Index)
+	if e.Name != "" {
+		task = fmt.Sprintf("task %q", e.Name)
+	}
+	var panicErr *PanicError
+	if errors.As(e.Err, &panicErr) {
+		return fmt.Sprintf("%s panicked: %v\n%s", task, panicErr.Value, panicErr.Stack)
+	}
+	return fmt.Sprintf("%s: %v", task, e.Err)
+}
+
+// Unwrap returns the original task error so errors.Is and errors.As work
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// TaskErrors returns the per-task errors recorded by parallel runs, in the order they were added
+func (c *TaskContext) TaskErrors() []*TaskError {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var taskErrs []*TaskError
+	for _, err := range c.multiErr {
+		var taskErr *TaskError
+		if errors.As(err, &taskErr) {
+			taskErrs = append(taskErrs, taskErr)
+		}
+	}
+	return taskErrs
+}
+
 // Define RunFn type at the top with other types
 type RunFn[T any] func() (T, error)
 
@@ -107,13 +198,90 @@ func Run[T any](ctx *TaskContext, fn RunFn[T]) T {
 	return result
 }
 
+// cacheEntry holds the memoized outcome of a RunCached call
+type cacheEntry struct {
+	once sync.Once
+	val  any
+	err  error
+}
+
+// RunCached behaves like Run but memoizes the outcome by key for the lifetime of the context.
+// fn runs at most once per key, even when called concurrently; later callers get the cached value.
+// A failed fn is cached as well, its error is recorded on the context only once.
+func RunCached[T any](ctx *TaskContext, key string, fn RunFn[T]) T {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero
+	}
+
+	ctx.cacheMu.Lock()
+	if ctx.cache == nil {
+		ctx.cache = make(map[string]*cacheEntry)
+	}
+	entry, ok := ctx.cache[key]
+	if !ok {
+		entry = &cacheEntry{}
+		ctx.cache[key] = entry
+	}
+	ctx.cacheMu.Unlock()
+
+	entry.once.Do(func() {
+		entry.val, entry.err = fn()
+		if entry.err != nil {
+			ctx.WithError(entry.err)
+		}
+	})
+
+	if entry.err != nil {
+		return zero
+	}
+	result, ok := entry.val.(T)
+	if !ok {
+		ctx.WithError(fmt.Errorf("cached value for key %q is %T, not %T", key, entry.val, zero))
+		return zero
+	}
+	return result
+}
+
 // Update RunParallel to use RunFn
+// Slots of failed tasks are left at the zero value of T, use RunParallelPtr
+// when a failed task must be told apart from one that returned the zero value.
 func RunParallel[T any](ctx *TaskContext, fns ...RunFn[T]) ([]T, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	results := make([]T, len(fns))
+	results, _ := RunParallelDetailed(ctx, fns...)
+	return results, ctx.Errors()
+}
+
+// RunParallelDetailed runs fns like RunParallel but reports the failures per task.
+// errs maps the 0-based position of every failed task to its error and is nil when
+// all tasks succeed. The errors are still recorded on ctx as *TaskError.
+// When ctx is already done no task runs and every position maps to the context error.
+func RunParallelDetailed[T any](ctx *TaskContext, fns ...RunFn[T]) (results []T, errs map[int]error) {
+	results = make([]T, len(fns))
+	if err := ctx.Err(); err != nil {
+		errs = make(map[int]error, len(fns))
+		for i := range fns {
+			errs[i] = err
+		}
+		return results, errs
+	}
+
+	results, errs = runParallel(ctx, fns)
+	for i := range fns {
+		if err, failed := errs[i]; failed {
+			ctx.AddError(&TaskError{Index: i + 1, Err: err})
+		}
+	}
+	return results, errs
+}
+
+// runParallel runs every fn in its own goroutine and returns the results with the
+// errors of the failed tasks by 0-based position, leaving error recording to the caller
+func runParallel[T any](ctx *TaskContext, fns []RunFn[T]) (results []T, errs map[int]error) {
+	results = make([]T, len(fns))
 	var resultsMu sync.Mutex
 	var wg sync.WaitGroup
 	wg.Add(len(fns))
@@ -122,21 +290,132 @@ func RunParallel[T any](ctx *TaskContext, fns ...RunFn[T]) ([]T, error) {
 		i, fn := i, fn
 		go func() {
 			defer wg.Done()
-			result, err := fn()
+			result, err := runTask(ctx, fn)
+			resultsMu.Lock()
+			defer resultsMu.Unlock()
 			if err != nil {
-				ctx.AddError(fmt.Errorf("task %d: %w", i+1, err))
+				if errs == nil {
+					errs = make(map[int]error)
+				}
+				errs[i] = err
 			} else {
-				resultsMu.Lock()
 				results[i] = result
-				resultsMu.Unlock()
 			}
 		}()
 	}
 
 	wg.Wait()
+	return results, errs
+}
+
+// RunParallelNamed runs tasks concurrently like RunParallel and returns their results by name.
+// Failed tasks are left out of the map and recorded on ctx as *TaskError carrying the name,
+// in name order so the joined error is deterministic. Index is the 1-based position of the
+// name in sorted order.
+func RunParallelNamed[T any](ctx *TaskContext, tasks map[string]RunFn[T]) (map[string]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	fns := make([]RunFn[T], len(names))
+	for i, name := range names {
+		fns[i] = tasks[name]
+	}
+	values, errs := runParallel(ctx, fns)
+
+	results := make(map[string]T, len(names))
+	for i, name := range names {
+		if err, failed := errs[i]; failed {
+			ctx.AddError(&TaskError{Index: i + 1, Name: name, Err: err})
+		} else {
+			results[name] = values[i]
+		}
+	}
 	return results, ctx.Errors()
 }
 
+// RunFirst races fns and returns the first successful result. The tasks share a context
+// derived from ctx that is cancelled as soon as one succeeds, so the others can stop early.
+// Only when every task fails are their errors recorded on ctx as *TaskError, in task order.
+func RunFirst[T any](ctx *TaskContext, fns ...RunFnCtx[T]) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if len(fns) == 0 {
+		return zero, errors.New("RunFirst needs at least one task")
+	}
+
+	raceCtx, cancel := context.WithCancel(ctx.Context)
+	defer cancel()
+
+	type raceResult struct {
+		index int
+		value T
+		err   error
+	}
+	// Buffered so the losing tasks can finish after the winner returned
+	resChan := make(chan raceResult, len(fns))
+	for i, fn := range fns {
+		i, fn := i, fn
+		go func() {
+			value, err := runTask(ctx, func() (T, error) { return fn(raceCtx) })
+			resChan <- raceResult{index: i, value: value, err: err}
+		}()
+	}
+
+	taskErrs := make([]*TaskError, 0, len(fns))
+	for range fns {
+		res := <-resChan
+		if res.err == nil {
+			return res.value, nil
+		}
+		taskErrs = append(taskErrs, &TaskError{Index: res.index + 1, Err: res.err})
+	}
+
+	slices.SortFunc(taskErrs, func(a, b *TaskError) int { return a.Index - b.Index })
+	for _, taskErr := range taskErrs {
+		ctx.AddError(taskErr)
+	}
+	return zero, ctx.Errors()
+}
+
+// RunParallelPtr runs fns like RunParallel but returns pointers to the results.
+// Successful tasks always get a non-nil pointer, failed tasks get nil.
+func RunParallelPtr[T any](ctx *TaskContext, fns ...RunFn[T]) ([]*T, error) {
+	ptrFns := make([]RunFn[*T], len(fns))
+	for i, fn := range fns {
+		fn := fn
+		ptrFns[i] = func() (*T, error) {
+			result, err := fn()
+			if err != nil {
+				return nil, err
+			}
+			return &result, nil
+		}
+	}
+	return RunParallel(ctx, ptrFns...)
+}
+
+// CollectOK returns the results of the tasks that succeeded, in index order.
+// errs maps the 0-based position of each failed task in results to its error, as returned by RunParallelDetailed.
+func CollectOK[T any](results []T, errs map[int]error) []T {
+	ok := make([]T, 0, len(results))
+	for i, result := range results {
+		if _, failed := errs[i]; failed {
+			continue
+		}
+		ok = append(ok, result)
+	}
+	return ok
+}
+
 // Update RunParallelWithLimit to use RunFn
 func RunParallelWithLimit[T any](ctx *TaskContext, limit int, fns ...RunFn[T]) ([]T, error) {
 	if err := ctx.Err(); err != nil {
@@ -156,9 +435,9 @@ func RunParallelWithLimit[T any](ctx *TaskContext, limit int, fns ...RunFn[T]) (
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			result, err := fn()
+			result, err := runTask(ctx, fn)
 			if err != nil {
-				ctx.AddError(fmt.Errorf("task %d: %w", i+1, err))
+				ctx.AddError(&TaskError{Index: i + 1, Err: err})
 			} else {
 				resultsMu.Lock()
 				results[i] = result
@@ -169,4 +448,46 @@ func RunParallelWithLimit[T any](ctx *TaskContext, limit int, fns ...RunFn[T]) (
 
 	wg.Wait()
 	return results, ctx.Errors()
-}
\ No newline at end of file
+}
+
+// IndexedResult is a task result tagged with the position of its RunFn in the call
+type IndexedResult[T any] struct {
+	Index int
+	Value T
+}
+
+// RunParallelWithLimitCompletionOrder runs fns like RunParallelWithLimit but returns
+// the successful results in the order the tasks finished. Index is the 0-based
+// position of the task in fns, failed tasks are only reported through the error.
+func RunParallelWithLimitCompletionOrder[T any](ctx *TaskContext, limit int, fns ...RunFn[T]) ([]IndexedResult[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]IndexedResult[T], 0, len(fns))
+	var resultsMu sync.Mutex
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	wg.Add(len(fns))
+
+	for i, fn := range fns {
+		i, fn := i, fn
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := runTask(ctx, fn)
+			if err != nil {
+				ctx.AddError(&TaskError{Index: i + 1, Err: err})
+			} else {
+				resultsMu.Lock()
+				results = append(results, IndexedResult[T]{Index: i, Value: result})
+				resultsMu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results, ctx.Errors()
+}
@@ -183,3 +183,468 @@ func TestDerivedContextFromTaskContext(t *testing.T) {
 	time.Sleep(2 * time.Second)
 	assert.ErrorIs(t, derivedCtx.Err(), context.DeadlineExceeded)
 }
+
+type quotaError struct {
+	Limit int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("quota of %d exceeded", e.Limit)
+}
+
+var errSentinel = errors.New("sentinel error")
+
+func TestRunParallel_PreservesErrorTypes(t *testing.T) {
+	ctx := NewTaskContext(context.Background())
+	_, err := RunParallel(ctx,
+		func() (int, error) { return 1, nil },
+		func() (int, error) { return 0, &quotaError{Limit: 10} },
+		func() (int, error) { return 0, fmt.Errorf("lookup: %w", errSentinel) },
+	)
+
+	t.Run("errors.As traverses wrapping", func(t *testing.T) {
+		var qErr *quotaError
+		assert.True(t, errors.As(err, &qErr))
+		assert.Equal(t, 10, qErr.Limit)
+	})
+
+	t.Run("errors.Is traverses wrapping", func(t *testing.T) {
+		assert.ErrorIs(t, err, errSentinel)
+		assert.ErrorIs(t, ctx.Err(), errSentinel)
+	})
+
+	t.Run("message keeps task prefix", func(t *testing.T) {
+		assert.Contains(t, err.Error(), "task 2: quota of 10 exceeded")
+		assert.Contains(t, err.Error(), "task 3: lookup: sentinel error")
+	})
+
+	t.Run("TaskErrors exposes unwrapped errors", func(t *testing.T) {
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 2)
+
+		byIndex := map[int]error{}
+		for _, taskErr := range taskErrs {
+			byIndex[taskErr.Index] = taskErr.Err
+		}
+		assert.IsType(t, &quotaError{}, byIndex[2])
+		assert.ErrorIs(t, byIndex[3], errSentinel)
+		assert.NotContains(t, byIndex[2].Error(), "task 2")
+	})
+}
+
+func TestTaskErrors_IgnoresPlainErrors(t *testing.T) {
+	ctx := NewTaskContext(context.Background())
+	ctx.AddError(errors.New("not a task error"))
+	assert.Empty(t, ctx.TaskErrors())
+}
+
+func TestRunCached(t *testing.T) {
+	t.Run("runs fn once per key", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		calls := atomic.Int32{}
+		fn := func() (int, error) {
+			calls.Add(1)
+			return 42, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, 42, RunCached(ctx, "answer", fn))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, 7, RunCached(ctx, "other", func() (int, error) { return 7, nil }))
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("caches failures", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		expectedErr := errors.New("expensive failure")
+		calls := 0
+		fn := func() (string, error) {
+			calls++
+			return "", expectedErr
+		}
+
+		assert.Equal(t, "", RunCached(ctx, "key", fn))
+		assert.Equal(t, "", RunCached(ctx, "key", fn))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, expectedErr, ctx.Err())
+	})
+
+	t.Run("type mismatch for the same key", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		_ = RunCached(ctx, "key", func() (int, error) { return 1, nil })
+		res := RunCached(ctx, "key", func() (string, error) { return "one", nil })
+		assert.Equal(t, "", res)
+		assert.Error(t, ctx.Err())
+	})
+}
+
+func TestMergeTaskErrors(t *testing.T) {
+	t.Run("joins errors grouped by context", func(t *testing.T) {
+		billing := NewTaskContext(context.Background())
+		shipping := NewTaskContext(context.Background())
+		healthy := NewTaskContext(context.Background())
+
+		billingErr := errors.New("card declined")
+		billing.AddError(billingErr)
+		_, _ = RunParallel(shipping,
+			func() (int, error) { return 0, &quotaError{Limit: 5} },
+			func() (int, error) { return 0, errSentinel },
+		)
+
+		err := MergeTaskErrors(billing, shipping, healthy, nil)
+		assert.ErrorIs(t, err, billingErr)
+		assert.ErrorIs(t, err, errSentinel)
+
+		var qErr *quotaError
+		assert.True(t, errors.As(err, &qErr))
+
+		var taskErr *TaskError
+		assert.True(t, errors.As(err, &taskErr))
+
+		// One group per context that actually failed
+		groups := err.(interface{ Unwrap() []error }).Unwrap()
+		assert.Len(t, groups, 2)
+		assert.Equal(t, billing.Errors(), groups[0])
+		assert.Equal(t, shipping.Errors(), groups[1])
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		assert.NoError(t, MergeTaskErrors(NewTaskContext(context.Background())))
+		assert.NoError(t, MergeTaskErrors())
+	})
+}
+
+func TestRunParallelPtr(t *testing.T) {
+	t.Run("failed slots are nil", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallelPtr(ctx,
+			func() (int, error) { return 0, nil },
+			func() (int, error) { return 0, errors.New("failed") },
+			func() (int, error) { return 3, nil },
+		)
+		assert.Error(t, err)
+		assert.Len(t, results, 3)
+		assert.NotNil(t, results[0])
+		assert.Equal(t, 0, *results[0])
+		assert.Nil(t, results[1])
+		assert.Equal(t, 3, *results[2])
+	})
+
+	t.Run("zero value slots for RunParallel", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallel(ctx,
+			func() (int, error) { return 0, errors.New("failed") },
+			func() (int, error) { return 2, nil },
+		)
+		assert.Error(t, err)
+		assert.Equal(t, []int{0, 2}, results)
+	})
+}
+
+func TestRunParallelWithLimitCompletionOrder(t *testing.T) {
+	t.Run("results arrive in completion order", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		delayed := func(v int, d time.Duration) RunFn[int] {
+			return func() (int, error) {
+				time.Sleep(d)
+				return v, nil
+			}
+		}
+
+		results, err := RunParallelWithLimitCompletionOrder(ctx, 3,
+			delayed(1, 60*time.Millisecond),
+			delayed(2, 30*time.Millisecond),
+			delayed(3, 0),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []IndexedResult[int]{
+			{Index: 2, Value: 3},
+			{Index: 1, Value: 2},
+			{Index: 0, Value: 1},
+		}, results)
+	})
+
+	t.Run("failed tasks are left out", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallelWithLimitCompletionOrder(ctx, 2,
+			func() (int, error) { return 0, errors.New("failed") },
+			func() (int, error) { return 2, nil },
+		)
+		assert.Error(t, err)
+		assert.Equal(t, []IndexedResult[int]{{Index: 1, Value: 2}}, results)
+		assert.Equal(t, 1, ctx.TaskErrors()[0].Index)
+	})
+}
+
+func TestCollectOK(t *testing.T) {
+	results := []int{0, 2, 0, 4}
+	errs := map[int]error{
+		0: errors.New("failed"),
+		2: errors.New("failed"),
+	}
+	assert.Equal(t, []int{2, 4}, CollectOK(results, errs))
+	assert.Equal(t, results, CollectOK(results, nil))
+	assert.Empty(t, CollectOK([]int{}, errs))
+}
+
+func TestPanicPolicy(t *testing.T) {
+	t.Run("recover as error", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background()).WithPanicPolicy(PanicRecoverAsError)
+		results, err := RunParallel(ctx,
+			func() (int, error) { return 1, nil },
+			func() (int, error) { panic("boom") },
+		)
+		assert.Equal(t, []int{1, 0}, results)
+
+		var panicErr *PanicError
+		assert.True(t, errors.As(err, &panicErr))
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.Contains(t, string(panicErr.Stack), "goroutine")
+		assert.Contains(t, err.Error(), "task 2 panicked: boom")
+	})
+
+	t.Run("recover with limit", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background()).WithPanicPolicy(PanicRecoverAsError)
+		panicValue := errors.New("panicked with error")
+		_, err := RunParallelWithLimit(ctx, 1,
+			func() (int, error) { panic(panicValue) },
+			func() (int, error) { return 2, nil },
+		)
+		assert.ErrorIs(t, err, panicValue)
+	})
+
+	t.Run("recover in Go", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background()).WithPanicPolicy(PanicRecoverAsError)
+		ctx.Go(func() error { panic("boom") })
+		var panicErr *PanicError
+		assert.True(t, errors.As(ctx.Wait(), &panicErr))
+	})
+
+	t.Run("recovers by default", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		var completed atomic.Int32
+		results, err := RunParallel(ctx,
+			func() (int, error) { completed.Add(1); return 1, nil },
+			func() (int, error) { panic("boom") },
+			func() (int, error) { completed.Add(1); return 3, nil },
+		)
+		assert.Equal(t, []int{1, 0, 3}, results)
+		assert.Equal(t, int32(2), completed.Load())
+		assert.Contains(t, err.Error(), "task 2 panicked: boom")
+		assert.Contains(t, err.Error(), "goroutine")
+
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 1)
+		assert.Equal(t, 2, taskErrs[0].Index)
+	})
+
+	t.Run("propagate opt in", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background()).WithPanicPolicy(PanicPropagate)
+		assert.Equal(t, PanicPropagate, ctx.getPanicPolicy())
+	})
+
+	t.Run("propagate calls through", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = PanicPropagate.Call(func() error { panic("boom") })
+		})
+	})
+}
+
+func TestRunParallelDetailed(t *testing.T) {
+	t.Run("maps failures by index", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		ctx := NewTaskContext(context.Background())
+		results, errs := RunParallelDetailed(ctx,
+			func() (int, error) { return 1, nil },
+			func() (int, error) { return 0, errFailed },
+			func() (int, error) { return 3, nil },
+		)
+		assert.Equal(t, []int{1, 0, 3}, results)
+		assert.Equal(t, map[int]error{1: errFailed}, errs)
+		assert.Equal(t, []int{1, 3}, CollectOK(results, errs))
+		assert.ErrorIs(t, ctx.Errors(), errFailed)
+	})
+
+	t.Run("no failures", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, errs := RunParallelDetailed(ctx, func() (int, error) { return 1, nil })
+		assert.Equal(t, []int{1}, results)
+		assert.Nil(t, errs)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		cancel()
+		ctx := NewTaskContext(parent)
+		called := false
+		_, errs := RunParallelDetailed(ctx,
+			func() (int, error) { called = true; return 1, nil },
+			func() (int, error) { called = true; return 2, nil },
+		)
+		assert.False(t, called)
+		assert.Equal(t, map[int]error{0: context.Canceled, 1: context.Canceled}, errs)
+	})
+}
+
+func TestRunFirst(t *testing.T) {
+	t.Run("fast task wins and slow one is cancelled", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		slowCancelled := make(chan error, 1)
+		result, err := RunFirst(ctx,
+			func(ctx context.Context) (string, error) {
+				select {
+				case <-ctx.Done():
+					slowCancelled <- ctx.Err()
+					return "", ctx.Err()
+				case <-time.After(time.Second):
+					return "slow", nil
+				}
+			},
+			func(ctx context.Context) (string, error) { return "fast", nil },
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", result)
+
+		select {
+		case err := <-slowCancelled:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("slow task was not cancelled")
+		}
+		assert.NoError(t, ctx.Errors())
+	})
+
+	t.Run("failure before success is ignored", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		result, err := RunFirst(ctx,
+			func(ctx context.Context) (int, error) { return 0, errors.New("replica down") },
+			func(ctx context.Context) (int, error) {
+				time.Sleep(10 * time.Millisecond)
+				return 2, nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result)
+		assert.NoError(t, ctx.Errors())
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		errFirst, errSecond := errors.New("first"), errors.New("second")
+		ctx := NewTaskContext(context.Background())
+		_, err := RunFirst(ctx,
+			func(ctx context.Context) (int, error) { return 0, errFirst },
+			func(ctx context.Context) (int, error) { return 0, errSecond },
+		)
+		assert.ErrorIs(t, err, errFirst)
+		assert.ErrorIs(t, err, errSecond)
+
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 2)
+		assert.Equal(t, 1, taskErrs[0].Index)
+		assert.Equal(t, 2, taskErrs[1].Index)
+	})
+}
+
+func TestNestedTaskContextErr(t *testing.T) {
+	errParent := errors.New("parent failed")
+	errChild := errors.New("child failed")
+
+	t.Run("own error wins over parent error", func(t *testing.T) {
+		parent := NewTaskContext(context.Background())
+		child := NewTaskContext(parent)
+		parent.WithError(errParent)
+		child.WithError(errChild)
+		assert.Equal(t, errChild, child.Err())
+		assert.Equal(t, errParent, parent.Err())
+	})
+
+	t.Run("parent error is inherited", func(t *testing.T) {
+		grandparent := NewTaskContext(context.Background())
+		child := NewTaskContext(NewTaskContext(grandparent))
+		grandparent.WithError(errParent)
+		assert.Equal(t, errParent, child.Err())
+	})
+
+	t.Run("cancellation wins over recorded errors", func(t *testing.T) {
+		base, cancel := context.WithCancel(context.Background())
+		parent := NewTaskContext(base)
+		child := NewTaskContext(parent)
+		parent.WithError(errParent)
+		child.WithError(errChild)
+		cancel()
+		assert.Equal(t, context.Canceled, child.Err())
+	})
+
+	t.Run("Run on child short circuits after child error", func(t *testing.T) {
+		parent := NewTaskContext(context.Background())
+		child := NewTaskContext(parent)
+		Run(child, func() (int, error) { return 0, errChild })
+
+		called := false
+		Run(child, func() (int, error) { called = true; return 1, nil })
+		assert.False(t, called)
+		assert.NoError(t, parent.Err())
+	})
+}
+
+func TestRunParallelNamed(t *testing.T) {
+	t.Run("results by name", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallelNamed(ctx, map[string]RunFn[int]{
+			"inventory": func() (int, error) { return 10, nil },
+			"pricing":   func() (int, error) { return 20, nil },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"inventory": 10, "pricing": 20}, results)
+	})
+
+	t.Run("errors are sorted by name", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallelNamed(ctx, map[string]RunFn[int]{
+			"shipping":  func() (int, error) { return 0, errFailed },
+			"address":   func() (int, error) { return 0, errFailed },
+			"inventory": func() (int, error) { return 10, nil },
+		})
+		assert.Equal(t, map[string]int{"inventory": 10}, results)
+		assert.EqualError(t, err, "task \"address\": failed\ntask \"shipping\": failed")
+
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 2)
+		assert.Equal(t, "address", taskErrs[0].Name)
+		assert.Equal(t, "shipping", taskErrs[1].Name)
+	})
+}
+
+func TestClearErrors(t *testing.T) {
+	t.Run("Run executes after clearing", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		Run(ctx, func() (int, error) { return 0, errors.New("phase one failed") })
+		assert.Error(t, ctx.Err())
+
+		assert.Same(t, ctx, ctx.ClearErrors())
+		assert.NoError(t, ctx.Err())
+		assert.NoError(t, ctx.Errors())
+
+		result := Run(ctx, func() (int, error) { return 2, nil })
+		assert.Equal(t, 2, result)
+	})
+
+	t.Run("cancellation is kept", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		ctx := NewTaskContext(parent)
+		ctx.WithError(errors.New("failed"))
+		cancel()
+
+		ctx.ClearErrors()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
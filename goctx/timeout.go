@@ -0,0 +1,58 @@
+package goctx
+
+import (
+	"context"
+	"time"
+)
+
+// RunFnCtx is a task that receives a context to observe cancellation and deadlines
+type RunFnCtx[T any] func(ctx context.Context) (T, error)
+
+// RunParallelWithTimeout runs fns like RunParallelWithLimit but gives every task at most
+// perTaskTimeout, passing it a context with that deadline. A task that overruns fails with
+// context.DeadlineExceeded and frees its slot even if it ignores the context.
+// Such a task is abandoned, not stopped: its goroutine keeps running until fn returns
+// and its result is discarded. A perTaskTimeout of 0 or less disables the timeout.
+func RunParallelWithTimeout[T any](ctx *TaskContext, limit int, perTaskTimeout time.Duration, fns ...RunFnCtx[T]) ([]T, error) {
+	timedFns := make([]RunFn[T], len(fns))
+	for i, fn := range fns {
+		timedFns[i] = withTimeout(ctx, perTaskTimeout, fn)
+	}
+	return RunParallelWithLimit(ctx, limit, timedFns...)
+}
+
+// withTimeout adapts fn to a RunFn that gives up once timeout has elapsed
+func withTimeout[T any](ctx *TaskContext, timeout time.Duration, fn RunFnCtx[T]) RunFn[T] {
+	return func() (T, error) {
+		if timeout <= 0 {
+			return fn(ctx.Context)
+		}
+		taskCtx, cancel := context.WithTimeout(ctx.Context, timeout)
+		defer cancel()
+
+		type taskResult struct {
+			value T
+			err   error
+		}
+		// Buffered so an abandoned task can still deliver its result and exit
+		done := make(chan taskResult, 1)
+		policy := ctx.getPanicPolicy()
+		go func() {
+			var res taskResult
+			res.err = policy.Call(func() error {
+				var err error
+				res.value, err = fn(taskCtx)
+				return err
+			})
+			done <- res
+		}()
+
+		select {
+		case res := <-done:
+			return res.value, res.err
+		case <-taskCtx.Done():
+			var zero T
+			return zero, taskCtx.Err()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package goctx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunParallelWithTimeout(t *testing.T) {
+	t.Run("hung task times out", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		ctx := NewTaskContext(context.Background())
+		start := time.Now()
+		results, err := RunParallelWithTimeout(ctx, 1, 20*time.Millisecond,
+			func(ctx context.Context) (int, error) {
+				// Ignores ctx on purpose
+				<-release
+				return 1, nil
+			},
+			func(ctx context.Context) (int, error) { return 2, nil },
+		)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, []int{0, 2}, results)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		taskErrs := ctx.TaskErrors()
+		assert.Len(t, taskErrs, 1)
+		assert.Equal(t, 1, taskErrs[0].Index)
+	})
+
+	t.Run("task sees the deadline", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		_, err := RunParallelWithTimeout(ctx, 2, 20*time.Millisecond,
+			func(ctx context.Context) (int, error) {
+				_, ok := ctx.Deadline()
+				assert.True(t, ok)
+				<-ctx.Done()
+				return 0, ctx.Err()
+			},
+		)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("errors and panics are reported", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		ctx := NewTaskContext(context.Background())
+		_, err := RunParallelWithTimeout(ctx, 2, time.Second,
+			func(ctx context.Context) (int, error) { return 0, errFailed },
+			func(ctx context.Context) (int, error) { panic("boom") },
+		)
+		assert.ErrorIs(t, err, errFailed)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		results, err := RunParallelWithTimeout(ctx, 2, 0,
+			func(ctx context.Context) (int, error) {
+				_, ok := ctx.Deadline()
+				assert.False(t, ok)
+				return 1, nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, results)
+	})
+}
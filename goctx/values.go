@@ -0,0 +1,29 @@
+package goctx
+
+// SetValue stores val under key on the context, safe to call from parallel tasks.
+// key must be comparable, like keys passed to context.WithValue.
+func (c *TaskContext) SetValue(key, val any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[any]any)
+	}
+	c.values[key] = val
+}
+
+// GetValue returns the value stored under key with SetValue
+func (c *TaskContext) GetValue(key any) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	val, ok := c.values[key]
+	return val, ok
+}
+
+// Value looks key up in the wrapped context first and falls back to the values set with SetValue
+func (c *TaskContext) Value(key any) any {
+	if val := c.Context.Value(key); val != nil {
+		return val
+	}
+	val, _ := c.GetValue(key)
+	return val
+}
@@ -0,0 +1,54 @@
+package goctx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey string
+
+func TestTaskContextValues(t *testing.T) {
+	t.Run("set and get", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		_, ok := ctx.GetValue("missing")
+		assert.False(t, ok)
+
+		ctx.SetValue("correlation_id", "abc")
+		val, ok := ctx.GetValue("correlation_id")
+		assert.True(t, ok)
+		assert.Equal(t, "abc", val)
+	})
+
+	t.Run("parallel tasks", func(t *testing.T) {
+		ctx := NewTaskContext(context.Background())
+		fns := make([]RunFn[int], 10)
+		for i := range fns {
+			i := i
+			fns[i] = func() (int, error) {
+				ctx.SetValue(fmt.Sprintf("task_%d", i), i)
+				return i, nil
+			}
+		}
+		_, err := RunParallel(ctx, fns...)
+		assert.NoError(t, err)
+		for i := range fns {
+			val, ok := ctx.GetValue(fmt.Sprintf("task_%d", i))
+			assert.True(t, ok)
+			assert.Equal(t, i, val)
+		}
+	})
+
+	t.Run("Value checks the wrapped context first", func(t *testing.T) {
+		parent := context.WithValue(context.Background(), ctxKey("user"), "from parent")
+		ctx := NewTaskContext(parent)
+		ctx.SetValue(ctxKey("user"), "from map")
+		ctx.SetValue(ctxKey("request"), "req-1")
+
+		assert.Equal(t, "from parent", ctx.Value(ctxKey("user")))
+		assert.Equal(t, "req-1", ctx.Value(ctxKey("request")))
+		assert.Nil(t, ctx.Value(ctxKey("missing")))
+	})
+}
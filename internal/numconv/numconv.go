@@ -0,0 +1,86 @@
+// Package numconv converts numbers between numeric types without losing information.
+// It backs stream_utils.SafeConvert, the dbutils struct mapping and the yaml_configs getters.
+package numconv
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// IsNumber reports whether k is an integer or float kind
+func IsNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k)
+}
+
+// Convert converts the number val to the numeric type t, or returns an error describing
+// what would be lost. Integer targets reject overflow, sign changes, NaN, infinities and
+// fractional values. Float targets reject integers that cannot be represented exactly and
+// overflow to infinity, while rounding a float to the nearest float of a smaller type is accepted.
+func Convert(val reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !IsNumber(val.Kind()) || !IsNumber(t.Kind()) {
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", val.Type(), t)
+	}
+
+	if isFloat(t.Kind()) {
+		converted := val.Convert(t)
+		f := converted.Float()
+		if isFloat(val.Kind()) {
+			if math.IsInf(f, 0) && !math.IsInf(val.Float(), 0) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %s", val, t)
+			}
+			return converted, nil
+		}
+		// Converting an out of range float back to an integer is implementation defined,
+		// so the rounded value must fit the source type before the round trip check
+		if !fitsInt(f, val.Type()) || !converted.Convert(val.Type()).Equal(val) {
+			return reflect.Value{}, fmt.Errorf("%v cannot be represented as %s", val, t)
+		}
+		return converted, nil
+	}
+
+	if isFloat(val.Kind()) {
+		f := val.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+			return reflect.Value{}, fmt.Errorf("%v is not an integer", val)
+		}
+		if !fitsInt(f, t) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %s", val, t)
+		}
+		return val.Convert(t), nil
+	}
+
+	// Between integers the conversion is lossless when converting back gives the
+	// original value with the same sign
+	converted := val.Convert(t)
+	if !converted.Convert(val.Type()).Equal(val) || isNegative(val) != isNegative(converted) {
+		return reflect.Value{}, fmt.Errorf("%v overflows %s", val, t)
+	}
+	return converted, nil
+}
+
+// fitsInt reports whether f lies within the range of the integer type t, false for NaN
+func fitsInt(f float64, t reflect.Type) bool {
+	bits := t.Bits()
+	if isUint(t.Kind()) {
+		return f >= 0 && f < math.Ldexp(1, bits)
+	}
+	return f >= -math.Ldexp(1, bits-1) && f < math.Ldexp(1, bits-1)
+}
+
+// isNegative reports whether the integer val is below zero
+func isNegative(val reflect.Value) bool {
+	return isInt(val.Kind()) && val.Int() < 0
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
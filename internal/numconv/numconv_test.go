@@ -0,0 +1,60 @@
+package numconv
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		to   any
+		want any
+	}{
+		{"widens integers", int32(-7), int64(0), int64(-7)},
+		{"narrows fitting integers", int64(255), uint8(0), uint8(255)},
+		{"named types", int64(5), time.Duration(0), time.Duration(5)},
+		{"integral floats", math.Ldexp(1, 62), int64(0), int64(1) << 62},
+		{"exact integers to float", int64(math.MinInt64), float64(0), -math.Ldexp(1, 63)},
+		{"rounds floats to a smaller float", 0.1, float32(0), float32(0.1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := Convert(reflect.ValueOf(tt.val), reflect.TypeOf(tt.to))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, converted.Interface())
+		})
+	}
+}
+
+func TestConvertLossy(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		to   any
+		err  string
+	}{
+		{"integer overflow", int64(300), int8(0), "300 overflows int8"},
+		{"sign change", int64(-1), uint64(0), "-1 overflows uint64"},
+		{"unsigned overflow", uint64(math.MaxUint64), int64(0), "18446744073709551615 overflows int64"},
+		{"fraction", 1.5, int64(0), "1.5 is not an integer"},
+		{"NaN", math.NaN(), uint8(0), "NaN is not an integer"},
+		{"float overflow", math.Ldexp(1, 63), int64(0), "9.223372036854776e+18 overflows int64"},
+		{"inexact integer", int64(1<<53 + 1), float64(0), "9007199254740993 cannot be represented as float64"},
+		// Rounds up to 2^63, which int64 cannot hold, whatever the platform does on the way back
+		{"rounds out of range", int64(math.MaxInt64), float64(0), "9223372036854775807 cannot be represented as float64"},
+		{"float to infinity", math.MaxFloat64, float32(0), "1.7976931348623157e+308 overflows float32"},
+		{"not a number", "1", int64(0), "cannot convert string to int64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Convert(reflect.ValueOf(tt.val), reflect.TypeOf(tt.to))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
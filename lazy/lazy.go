@@ -0,0 +1,44 @@
+package lazy
+
+import "sync"
+
+// Lazy computes a value on first use and caches it until Reset is called.
+// Unlike sync.Once a failed computation is not cached, the next Get tries again.
+type Lazy[T any] struct {
+	mu    sync.Mutex
+	init  func() (T, error)
+	value T
+	done  bool
+}
+
+// New returns a Lazy that computes its value with init
+func New[T any](init func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+// Get returns the cached value, computing it first if needed.
+// Concurrent callers wait for a single computation.
+func (l *Lazy[T]) Get() (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return l.value, nil
+	}
+	value, err := l.init()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	l.value, l.done = value, true
+	return value, nil
+}
+
+// Reset drops the cached value so the next Get computes it again
+func (l *Lazy[T]) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zero T
+	l.value, l.done = zero, false
+}
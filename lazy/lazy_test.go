@@ -0,0 +1,67 @@
+package lazy
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("computes once", func(t *testing.T) {
+		calls := atomic.Int32{}
+		l := New(func() (int, error) {
+			calls.Add(1)
+			return 42, nil
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := l.Get()
+				assert.NoError(t, err)
+				assert.Equal(t, 42, v)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("reset recomputes", func(t *testing.T) {
+		calls := 0
+		l := New(func() (int, error) {
+			calls++
+			return calls, nil
+		})
+
+		v, _ := l.Get()
+		assert.Equal(t, 1, v)
+		l.Reset()
+		v, _ = l.Get()
+		assert.Equal(t, 2, v)
+		v, _ = l.Get()
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		fail := true
+		l := New(func() (string, error) {
+			if fail {
+				return "", errors.New("not ready")
+			}
+			return "ready", nil
+		})
+
+		_, err := l.Get()
+		assert.Error(t, err)
+
+		fail = false
+		v, err := l.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "ready", v)
+	})
+}
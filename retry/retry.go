@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// RetryPolicy describes how often and when a failed call is retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls, values below 1 mean a single call
+	MaxAttempts int
+	// Backoff returns the wait before the given retry, attempt starts at 1 for the first retry.
+	// A nil Backoff retries immediately.
+	Backoff func(attempt int) time.Duration
+	// IsRetryable reports whether err is worth another attempt, nil retries every error
+	IsRetryable func(err error) bool
+}
+
+// Constant waits the same delay before every retry
+func Constant(delay time.Duration) func(attempt int) time.Duration {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential doubles the delay before every retry, starting at base.
+// The delay stops growing at the largest time.Duration instead of overflowing.
+func Exponential(base time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		if attempt < 1 {
+			attempt = 1
+		}
+		if base <= 0 {
+			return base
+		}
+		shift := attempt - 1
+		if shift >= 63 || base > math.MaxInt64>>shift {
+			return math.MaxInt64
+		}
+		return base << shift
+	}
+}
+
+// Retry calls fn until it succeeds, returns a non retryable error or policy.MaxAttempts is reached.
+// The error of the last attempt is returned as is. Waiting between attempts stops
+// with the context error as soon as ctx is done.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var (
+		res T
+		err error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := wait(ctx, policy.Backoff, attempt); err != nil {
+				return res, err
+			}
+		}
+		if res, err = fn(); err == nil {
+			return res, nil
+		}
+		if policy.IsRetryable != nil && !policy.IsRetryable(err) {
+			return res, err
+		}
+	}
+	return res, err
+}
+
+// wait sleeps for the backoff of attempt, giving up once ctx is done
+func wait(ctx context.Context, backoff func(attempt int) time.Duration, attempt int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if backoff == nil {
+		return nil
+	}
+	delay := backoff(attempt)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
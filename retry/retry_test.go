@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTransient = errors.New("transient")
+
+func TestRetry(t *testing.T) {
+	t.Run("succeeds after retries", func(t *testing.T) {
+		calls := 0
+		res, err := Retry(context.Background(), RetryPolicy{MaxAttempts: 3}, func() (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errTransient
+			}
+			return 42, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 42, res)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("returns last error once attempts run out", func(t *testing.T) {
+		calls := 0
+		_, err := Retry(context.Background(), RetryPolicy{MaxAttempts: 2}, func() (int, error) {
+			calls++
+			return 0, errTransient
+		})
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("zero attempts still calls once", func(t *testing.T) {
+		calls := 0
+		_, err := Retry(context.Background(), RetryPolicy{}, func() (int, error) {
+			calls++
+			return 0, errTransient
+		})
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops on non retryable error", func(t *testing.T) {
+		permanent := errors.New("permanent")
+		calls := 0
+		policy := RetryPolicy{
+			MaxAttempts: 5,
+			IsRetryable: func(err error) bool { return errors.Is(err, errTransient) },
+		}
+		_, err := Retry(context.Background(), policy, func() (int, error) {
+			calls++
+			return 0, permanent
+		})
+		assert.ErrorIs(t, err, permanent)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("passes attempt to backoff", func(t *testing.T) {
+		var seen []int
+		policy := RetryPolicy{
+			MaxAttempts: 3,
+			Backoff: func(attempt int) time.Duration {
+				seen = append(seen, attempt)
+				return time.Millisecond
+			},
+		}
+		_, _ = Retry(context.Background(), policy, func() (int, error) {
+			return 0, errTransient
+		})
+		assert.Equal(t, []int{1, 2}, seen)
+	})
+
+	t.Run("cancelled context stops waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		calls := 0
+		start := time.Now()
+		_, err := Retry(ctx, RetryPolicy{MaxAttempts: 3, Backoff: Constant(time.Second)}, func() (int, error) {
+			calls++
+			return 0, errTransient
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestExponential(t *testing.T) {
+	backoff := Exponential(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, time.Duration(math.MaxInt64), backoff(41))
+	assert.Equal(t, time.Duration(math.MaxInt64), backoff(1000))
+}
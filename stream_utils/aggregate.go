@@ -0,0 +1,90 @@
+package stream_utils
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmptyStream is returned by terminals such as MinBy or Average when the chain produces no items
+var ErrEmptyStream = errors.New("stream has no items")
+
+// Aggregator folds the items of a stream into an accumulated value of type A.
+// Init provides the starting value and Accumulate merges one item into it.
+type Aggregator[T any, A any] interface {
+	Init() A
+	Accumulate(acc A, item T) (A, error)
+}
+
+// Aggregate runs the transformer and folds its final items with agg.
+// It stops at the first error returned by the chain or by Accumulate.
+func Aggregate[T, R, A any](t *Transformer[T, R], agg Aggregator[R, A]) (A, error) {
+	acc := agg.Init()
+	err := t.stream(context.Background(), func(item R) (err error) {
+		acc, err = agg.Accumulate(acc, item)
+		return err
+	})
+	return acc, err
+}
+
+// MinBy runs the transformer and returns its smallest final item according to less.
+// The first of several equal items is returned, ErrEmptyStream when there are none.
+func MinBy[T, R any](t *Transformer[T, R], less func(a, b R) bool) (R, error) {
+	var res R
+	found := false
+	err := t.stream(context.Background(), func(item R) error {
+		if !found || less(item, res) {
+			res, found = item, true
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	if !found {
+		return res, ErrEmptyStream
+	}
+	return res, nil
+}
+
+// MaxBy runs the transformer and returns its largest final item according to less.
+// The first of several equal items is returned, ErrEmptyStream when there are none.
+func MaxBy[T, R any](t *Transformer[T, R], less func(a, b R) bool) (R, error) {
+	return MinBy(t, func(a, b R) bool { return less(b, a) })
+}
+
+// Sum runs the transformer and adds up its final items, ErrEmptyStream when there are none
+func Sum[T any, R Number](t *Transformer[T, R]) (R, error) {
+	var sum R
+	count := 0
+	err := t.stream(context.Background(), func(item R) error {
+		sum += item
+		count++
+		return nil
+	})
+	if err != nil {
+		return sum, err
+	}
+	if count == 0 {
+		return sum, ErrEmptyStream
+	}
+	return sum, nil
+}
+
+// Average runs the transformer and returns the mean of its final items, ErrEmptyStream when there are none.
+// Items are added up as float64 so integer sums do not overflow.
+func Average[T any, R Number](t *Transformer[T, R]) (float64, error) {
+	var sum float64
+	count := 0
+	err := t.stream(context.Background(), func(item R) error {
+		sum += float64(item)
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrEmptyStream
+	}
+	return sum / float64(count), nil
+}
@@ -0,0 +1,104 @@
+package stream_utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// histogram counts items into buckets of the given width
+type histogram struct {
+	width int64
+}
+
+func (h histogram) Init() map[int64]int {
+	return map[int64]int{}
+}
+
+func (h histogram) Accumulate(acc map[int64]int, item int64) (map[int64]int, error) {
+	acc[item/h.width*h.width]++
+	return acc, nil
+}
+
+type failingAggregator struct{}
+
+func (failingAggregator) Init() int { return 0 }
+
+func (failingAggregator) Accumulate(acc int, item int64) (int, error) {
+	if acc == 1 {
+		return acc, ErrTest
+	}
+	return acc + 1, nil
+}
+
+func TestAggregate(t *testing.T) {
+	floatingStrings := []string{"1", "4", "12", "15", "17", "25"}
+
+	res, err := Aggregate(
+		NewTransformer[string, int64](floatingStrings).
+			Transform(MapIt[string, int64](func(item string) (int64, error) { return strconv.ParseInt(item, 10, 64) })),
+		histogram{width: 10},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int{0: 2, 10: 3, 20: 1}, res)
+}
+
+func TestAggregateErrors(t *testing.T) {
+	_, err := Aggregate(
+		NewTransformer[string, int64]([]string{"1", "x"}).
+			Transform(MapIt[string, int64](func(item string) (int64, error) { return strconv.ParseInt(item, 10, 64) })),
+		histogram{width: 10},
+	)
+	assert.Error(t, err)
+
+	acc, err := Aggregate(NewTransformer[int64, int64]([]int64{1, 2, 3}), failingAggregator{})
+	assert.Equal(t, ErrTest, err)
+	assert.Equal(t, 1, acc)
+}
+
+func TestNumericTerminals(t *testing.T) {
+	parsed := func(items ...string) *Transformer[string, float64] {
+		return NewTransformer[string, float64](items).
+			Transform(MapIt[string, float64](func(item string) (float64, error) { return strconv.ParseFloat(item, 64) }))
+	}
+	less := func(a, b float64) bool { return a < b }
+
+	min, err := MinBy(parsed("0.5", "-2.5", "7", "-2.5"), less)
+	assert.NoError(t, err)
+	assert.Equal(t, -2.5, min)
+
+	max, err := MaxBy(parsed("0.5", "-2.5", "7"), less)
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, max)
+
+	sum, err := Sum(parsed("0.5", "1.5", "2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, sum)
+
+	avg, err := Average(NewTransformer[int, int]([]int{1, 2, 4}))
+	assert.NoError(t, err)
+	assert.InDelta(t, 7.0/3, avg, 1e-9)
+
+	intSum, err := Sum(NewTransformer[int, int]([]int{1, 2, 4}))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, intSum)
+}
+
+func TestNumericTerminalsErrors(t *testing.T) {
+	empty := func() *Transformer[int, int] { return NewTransformer[int, int](nil) }
+	less := func(a, b int) bool { return a < b }
+
+	_, err := MinBy(empty(), less)
+	assert.ErrorIs(t, err, ErrEmptyStream)
+	_, err = MaxBy(empty(), less)
+	assert.ErrorIs(t, err, ErrEmptyStream)
+	_, err = Sum(empty())
+	assert.ErrorIs(t, err, ErrEmptyStream)
+	_, err = Average(empty())
+	assert.ErrorIs(t, err, ErrEmptyStream)
+
+	_, err = Sum(NewTransformer[string, int]([]string{"x"}).Transform(MapIt[string, int](strconv.Atoi)))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrEmptyStream)
+}
@@ -0,0 +1,184 @@
+package stream_utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// itemMapper is implemented by mappers that can run one item at a time on a channel source
+type itemMapper interface {
+	mapItem(ctx context.Context, i int, item any, reject func(item any)) (out any, keep bool, err error)
+	mapsItems() bool
+	// forRun returns the mapper to use for one run, with its own state
+	forRun() itemMapper
+	// isExhausted reports whether no item from position seen of the stage input on can pass the stage
+	isExhausted(seen int) bool
+}
+
+// errStopStream is returned by an emit function to stop reading items, stream reports it as success
+var errStopStream = errors.New("stop stream")
+
+// FromChannel builds a transformer reading its items from src until it is closed.
+// When every stage maps single items, Result, ToChannel and the terminals such as ForEach or
+// AnyMatch run the chain lazily, element by element, and stop reading src once they are done.
+// Stages such as SortBy that need every item make Result read the whole source first.
+func FromChannel[T, R any](src <-chan T) *Transformer[T, R] {
+	return &Transformer[T, R]{
+		items:  []T(nil),
+		source: src,
+	}
+}
+
+// ToChannel runs the chain in the background and sends every final item on the returned channel.
+// With a FromChannel source each element goes through all stages before the next one is read,
+// so every stage must map single items, stages such as SortBy or ChunkIt fail the chain.
+// The error channel receives exactly one value, nil on success, once the item channel is closed.
+// Cancelling ctx stops reading the source and sending items.
+func (t *Transformer[T, R]) ToChannel(ctx context.Context) (<-chan R, <-chan error) {
+	out := make(chan R)
+	errc := make(chan error, 1)
+	go func() {
+		var err error
+		if t.source != nil {
+			_, err = t.itemStages()
+		}
+		if err == nil {
+			err = t.stream(ctx, func(item R) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case out <- item:
+					return nil
+				}
+			})
+		}
+		close(out)
+		errc <- err
+		close(errc)
+	}()
+	return out, errc
+}
+
+// stream runs the chain and hands every final item to emit, until emit returns errStopStream.
+// A FromChannel source is read lazily unless a stage needs every item.
+func (t *Transformer[T, R]) stream(ctx context.Context, emit func(item R) error) error {
+	if t.source != nil {
+		if stages, err := t.itemStages(); err == nil {
+			itemErrs, err := t.streamSource(ctx, stages, nil, emit)
+			if errors.Is(err, errStopStream) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			return errors.Join(itemErrs...)
+		}
+	}
+
+	items, err := t.ResultCtx(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := emit(item); errors.Is(err, errStopStream) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemStages returns the stages prepared for one lazy run over the channel source,
+// or an error naming the first stage that needs every item
+func (t *Transformer[T, R]) itemStages() ([]itemMapper, error) {
+	stages := make([]itemMapper, len(t.mappers))
+	for i, mapper := range t.mappers {
+		stage, ok := mapper.(itemMapper)
+		if !ok || !stage.mapsItems() {
+			return nil, fmt.Errorf("stage %d needs every item and cannot run on a channel source", i+1)
+		}
+		stages[i] = stage.forRun()
+	}
+	return stages, nil
+}
+
+// streamSource reads the channel source one element at a time, runs it through stages and hands
+// the final items to emit. Items dropped by a filter are reported to onReject when it is set.
+// When errors are collected the failing items are returned as itemErrs.
+func (t *Transformer[T, R]) streamSource(ctx context.Context, stages []itemMapper, onReject func(stage int, item any), emit func(item R) error) (itemErrs []error, err error) {
+	rejects := make([]func(item any), len(stages))
+	if onReject != nil {
+		for i := range stages {
+			stage := i + 1
+			rejects[i] = func(item any) { onReject(stage, item) }
+		}
+	}
+
+	// seen counts the items that reached each stage, giving their position in the stage input
+	seen := make([]int, len(stages))
+	for {
+		for i, stage := range stages {
+			if stage.isExhausted(seen[i]) {
+				// Nothing read from now on could be emitted, so the source is left unread
+				return itemErrs, nil
+			}
+		}
+
+		var item any
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case src, ok := <-t.source:
+			if !ok {
+				return itemErrs, nil
+			}
+			item = src
+		}
+
+		keep := true
+		for i, stage := range stages {
+			var err error
+			pos := seen[i]
+			seen[i]++
+			if item, keep, err = stage.mapItem(ctx, pos, item, rejects[i]); err != nil {
+				if !t.collectErrors {
+					return nil, err
+				}
+				// A failed item never reaches the next stage, even when the mapper asked to keep it
+				keep = false
+				itemErrs = append(itemErrs, &ItemError{Stage: i + 1, Index: pos, Err: err})
+			}
+			if !keep {
+				break
+			}
+		}
+		if !keep {
+			continue
+		}
+
+		res, ok := item.(R)
+		if !ok {
+			return nil, fmt.Errorf("bad type casting: chain produces %T, expected %v", item, typeOf[R]())
+		}
+		if err := emit(res); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// drain reads src until it is closed or ctx is done
+func drain[T any](ctx context.Context, src <-chan T) ([]T, error) {
+	var items []T
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case item, ok := <-src:
+			if !ok {
+				return items, nil
+			}
+			items = append(items, item)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package stream_utils
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func produce(items ...string) <-chan string {
+	src := make(chan string)
+	go func() {
+		defer close(src)
+		for _, item := range items {
+			src <- item
+		}
+	}()
+	return src
+}
+
+func collect[R any](out <-chan R, errc <-chan error) ([]R, error) {
+	var items []R
+	for item := range out {
+		items = append(items, item)
+	}
+	return items, <-errc
+}
+
+func TestFromChannelToChannel(t *testing.T) {
+	t.Run("maps lazily per element", func(t *testing.T) {
+		var trace []string
+		out, errc := FromChannel[string, int](produce("1", "2", "3", "4")).
+			Transform(MapIt[string, int](func(item string) (int, error) {
+				trace = append(trace, "map "+item)
+				return strconv.Atoi(item)
+			})).
+			Transform(FilterItSimple(func(item int) bool {
+				trace = append(trace, "filter "+strconv.Itoa(item))
+				return item%2 == 0
+			})).
+			ToChannel(context.Background())
+
+		res, err := collect(out, errc)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 4}, res)
+		assert.Equal(t, []string{"map 1", "filter 1", "map 2", "filter 2", "map 3", "filter 3", "map 4", "filter 4"}, trace)
+	})
+
+	t.Run("stops on mapping error", func(t *testing.T) {
+		out, errc := FromChannel[string, int](produce("1", "x", "3")).
+			Transform(MapIt[string, int](strconv.Atoi)).
+			ToChannel(context.Background())
+		res, err := collect(out, errc)
+		assert.Error(t, err)
+		assert.Equal(t, []int{1}, res)
+	})
+
+	t.Run("collects errors", func(t *testing.T) {
+		out, errc := FromChannel[string, int](produce("1", "x", "3")).
+			CollectErrors().
+			Transform(MapIt[string, int](strconv.Atoi)).
+			ToChannel(context.Background())
+		res, err := collect(out, errc)
+		assert.Equal(t, []int{1, 3}, res)
+		assert.ErrorContains(t, err, "stage 1 item 1")
+	})
+
+	t.Run("reports the position in the stage input", func(t *testing.T) {
+		out, errc := FromChannel[string, int](produce("", "1", "", "x")).
+			CollectErrors().
+			Transform(FilterItSimple(func(item string) bool { return item != "" })).
+			Transform(MapIt[string, int](strconv.Atoi)).
+			ToChannel(context.Background())
+		res, err := collect(out, errc)
+		assert.Equal(t, []int{1}, res)
+		var itemErr *ItemError
+		assert.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 2, itemErr.Stage)
+		assert.Equal(t, 1, itemErr.Index)
+	})
+
+	t.Run("drops failed items the mapper keeps", func(t *testing.T) {
+		keepFailed := func(item string, err error) (int, bool, error) { return -1, true, err }
+		out, errc := FromChannel[string, int](produce("1", "x", "3")).
+			CollectErrors().
+			Transform(MapItRecover[string, int](strconv.Atoi, keepFailed)).
+			ToChannel(context.Background())
+		res, err := collect(out, errc)
+		assert.Equal(t, []int{1, 3}, res)
+		assert.ErrorContains(t, err, "stage 1 item 1")
+	})
+
+	t.Run("rejects whole slice stages", func(t *testing.T) {
+		out, errc := FromChannel[string, string](produce("b", "a")).
+			Transform(Sorted[string]()).
+			ToChannel(context.Background())
+		_, err := collect(out, errc)
+		assert.ErrorContains(t, err, "stage 1 needs every item")
+	})
+
+	t.Run("cancel stops consumption", func(t *testing.T) {
+		src := make(chan int)
+		defer close(src)
+		ctx, cancel := context.WithCancel(context.Background())
+		out, errc := FromChannel[int, int](src).
+			Transform(MapItSimple(func(item int) int { return item * 2 })).
+			ToChannel(ctx)
+
+		src <- 1
+		assert.Equal(t, 2, <-out)
+		cancel()
+		select {
+		case err := <-errc:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("stream did not stop after cancel")
+		}
+	})
+}
+
+func TestFromChannelResult(t *testing.T) {
+	res, err := FromChannel[string, string](produce("b", "c", "a")).
+		Transform(Sorted[string]()).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, res)
+}
+
+func TestFromChannelResultIsLazy(t *testing.T) {
+	var trace []string
+	res, rejected, err := FromChannel[string, int](produce("1", "2", "3")).
+		Transform(MapIt[string, int](func(item string) (int, error) {
+			trace = append(trace, "map "+item)
+			return strconv.Atoi(item)
+		})).
+		Transform(FilterItSimple(func(item int) bool {
+			trace = append(trace, "filter "+strconv.Itoa(item))
+			return item != 2
+		})).
+		ResultWithRejected()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, res)
+	assert.Equal(t, []RejectedItem{{Stage: 2, Item: 2}}, rejected)
+	assert.Equal(t, []string{"map 1", "filter 1", "map 2", "filter 2", "map 3", "filter 3"}, trace)
+}
+
+func TestFromChannelAnyMatchStopsReading(t *testing.T) {
+	// The source never ends, so AnyMatch only returns if it stops reading at the first match
+	src := make(chan int)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			case src <- i:
+			}
+		}
+	}()
+
+	var mapped int
+	found, err := AnyMatch(
+		FromChannel[int, int](src).Transform(Peek(func(item int) { mapped++ })),
+		func(item int) (bool, error) { return item == 3, nil },
+	)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 3, mapped)
+}
+
+func TestSliceToChannel(t *testing.T) {
+	out, errc := NewTransformer[int, int]([]int{3, 1, 2}).
+		Transform(Sorted[int]()).
+		ToChannel(context.Background())
+	res, err := collect(out, errc)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, res)
+}
+
+func TestFromChannelIndexedMapIt(t *testing.T) {
+	out, errc := FromChannel[string, string](produce("a", "", "b")).
+		Transform(FilterItSimple(func(item string) bool { return item != "" })).
+		Transform(IndexedMapIt(func(i int, item string) (string, error) {
+			return strconv.Itoa(i) + ":" + item, nil
+		})).
+		ToChannel(context.Background())
+	res, err := collect(out, errc)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0:a", "1:b"}, res)
+}
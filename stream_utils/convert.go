@@ -0,0 +1,48 @@
+package stream_utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mahadev-k/go-utils/internal/numconv"
+)
+
+// ErrLossyConversion is returned when a numeric conversion would overflow or lose precision
+var ErrLossyConversion = errors.New("lossy numeric conversion")
+
+// Number is the set of numeric types SafeConvert works with
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SafeConvert converts v to To and returns ErrLossyConversion instead of silently
+// wrapping or truncating. Integer targets reject overflow, sign changes, NaN,
+// infinities and fractional values. Float targets reject integers that cannot be
+// represented exactly and overflow to infinity, while rounding a float to the
+// nearest float of a smaller type is accepted.
+func SafeConvert[From, To Number](v From) (To, error) {
+	var to To
+	converted, err := numconv.Convert(reflect.ValueOf(v), reflect.TypeOf(to))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrLossyConversion, err)
+	}
+	return converted.Interface().(To), nil
+}
+
+// ToInt64 returns a mapper converting numbers to int64 with SafeConvert
+func ToInt64[T Number]() *MapRunner[T, int64] {
+	return MapIt(SafeConvert[T, int64])
+}
+
+// ToInt32 returns a mapper converting numbers to int32 with SafeConvert
+func ToInt32[T Number]() *MapRunner[T, int32] {
+	return MapIt(SafeConvert[T, int32])
+}
+
+// ToFloat64 returns a mapper converting numbers to float64 with SafeConvert
+func ToFloat64[T Number]() *MapRunner[T, float64] {
+	return MapIt(SafeConvert[T, float64])
+}
@@ -0,0 +1,96 @@
+package stream_utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeConvert(t *testing.T) {
+	t.Run("lossless conversions", func(t *testing.T) {
+		i32, err := SafeConvert[float64, int32](-42)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(-42), i32)
+
+		u8, err := SafeConvert[int64, uint8](255)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(255), u8)
+
+		i64, err := SafeConvert[float64, int64](math.Ldexp(1, 62))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1)<<62, i64)
+
+		minInt, err := SafeConvert[int64, float64](math.MinInt64)
+		assert.NoError(t, err)
+		assert.Equal(t, -math.Ldexp(1, 63), minInt)
+
+		f32, err := SafeConvert[float64, float32](0.1)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.1), f32)
+	})
+
+	t.Run("lossy conversions", func(t *testing.T) {
+		_, err := SafeConvert[float64, int32](math.MaxInt32 + 1)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[int64, int32](math.MinInt32 - 1)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[int64, uint64](-1)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[uint64, int64](math.MaxUint64)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[float64, int64](1.5)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[float64, int64](math.Ldexp(1, 63))
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[float64, uint8](math.NaN())
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[int64, float64](1<<53 + 1)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[uint64, float64](math.MaxUint64)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		// Rounds up to 2^63, which int64 cannot hold, whatever the platform does on the way back
+		_, err = SafeConvert[int64, float64](math.MaxInt64)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[int32, float32](math.MaxInt32)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+
+		_, err = SafeConvert[float64, float32](math.MaxFloat64)
+		assert.ErrorIs(t, err, ErrLossyConversion)
+	})
+}
+
+func TestConversionMappers(t *testing.T) {
+	res, err := NewTransformer[float64, int32]([]float64{1, 2, 3}).
+		Transform(ToInt32[float64]()).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, res)
+
+	_, err = NewTransformer[float64, int32]([]float64{1, math.MaxInt32 * 2}).
+		Transform(ToInt32[float64]()).
+		Result()
+	assert.ErrorIs(t, err, ErrLossyConversion)
+
+	res64, err := NewTransformer[int32, int64]([]int32{-1, 7}).
+		Transform(ToInt64[int32]()).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{-1, 7}, res64)
+
+	resFloat, err := NewTransformer[int, float64]([]int{3}).
+		Transform(ToFloat64[int]()).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3}, resFloat)
+}
@@ -0,0 +1,25 @@
+package stream_utils
+
+// DistinctIt drops the items equal to an earlier item, keeping the first one seen
+func DistinctIt[T comparable]() *MapRunner[T, T] {
+	return DistinctByIt(func(item T) T { return item })
+}
+
+// DistinctByIt drops the items whose key equals the key of an earlier item, keeping the first one seen.
+// Items are checked one at a time, so it also runs on a FromChannel source.
+func DistinctByIt[T any, K comparable](keyFn func(item T) K) *MapRunner[T, T] {
+	return &MapRunner[T, T]{
+		newRunFilter: func() func(i int, item T) bool {
+			seen := make(map[K]struct{})
+			return func(i int, item T) bool {
+				key := keyFn(item)
+				if _, ok := seen[key]; ok {
+					return false
+				}
+				seen[key] = struct{}{}
+				return true
+			}
+		},
+		err: nil,
+	}
+}
@@ -0,0 +1,48 @@
+package stream_utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinctIt(t *testing.T) {
+	transformer := NewTransformer[int, int]([]int{3, 1, 3, 2, 1, 4, 2}).
+		Transform(DistinctIt[int]())
+
+	res, err := transformer.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2, 4}, res)
+}
+
+func TestDistinctByIt(t *testing.T) {
+	res, err := NewTransformer[string, string]([]string{"Go", "rust", "GO", "Rust", "zig"}).
+		Transform(DistinctByIt(strings.ToLower)).
+		Transform(MapItSimple(strings.ToUpper)).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GO", "RUST", "ZIG"}, res)
+}
+
+func TestDistinctItRunsTwice(t *testing.T) {
+	distinct := DistinctIt[int]()
+	for i := 0; i < 2; i++ {
+		res, err := distinct.Result([]int{1, 1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, res)
+	}
+}
+
+func TestDistinctItFromChannel(t *testing.T) {
+	stage := DistinctIt[string]()
+	for i := 0; i < 2; i++ {
+		out, errc := FromChannel[string, string](produce("a", "b", "a", "c", "b")).
+			Transform(stage).
+			ToChannel(context.Background())
+		res, err := collect(out, errc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, res)
+	}
+}
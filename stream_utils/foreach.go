@@ -0,0 +1,17 @@
+package stream_utils
+
+import "context"
+
+// Peek passes every item through unchanged after calling fn with it, handy for logging a chain
+func Peek[T any](fn func(item T)) *MapRunner[T, T] {
+	return MapItSimple(func(item T) T {
+		fn(item)
+		return item
+	})
+}
+
+// ForEach runs the transformer and calls fn with every final item in order.
+// It stops at the first error returned by the chain or by fn.
+func ForEach[T, R any](t *Transformer[T, R], fn func(item R) error) error {
+	return t.stream(context.Background(), fn)
+}
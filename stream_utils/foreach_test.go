@@ -0,0 +1,49 @@
+package stream_utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeek(t *testing.T) {
+	var peeked []int
+	res, err := NewTransformer[string, int]([]string{"1", "2", "3"}).
+		Transform(MapIt[string, int](strconv.Atoi)).
+		Transform(Peek(func(item int) { peeked = append(peeked, item) })).
+		Transform(FilterItSimple(func(item int) bool { return item != 2 })).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, res)
+	assert.Equal(t, []int{1, 2, 3}, peeked)
+}
+
+func TestForEach(t *testing.T) {
+	calls := 0
+	err := ForEach(NewTransformer[int, int]([]int{1, 2, 3}), func(item int) error {
+		calls++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = ForEach(NewTransformer[int, int]([]int{1, 2, 3}), func(item int) error {
+		calls++
+		if item == 2 {
+			return ErrTest
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrTest)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = ForEach(NewTransformer[string, int]([]string{"x"}).Transform(MapIt[string, int](strconv.Atoi)), func(item int) error {
+		calls++
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Zero(t, calls)
+}
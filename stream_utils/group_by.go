@@ -0,0 +1,37 @@
+package stream_utils
+
+import "context"
+
+// GroupBy buckets items by the key returned by keyFn.
+// Items keep their input order within each bucket.
+func GroupBy[T any, K comparable](items []T, keyFn func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, item := range items {
+		key := keyFn(item)
+		groups[key] = append(groups[key], item)
+	}
+	return groups
+}
+
+// GroupByCount counts the items sharing each key returned by keyFn
+func GroupByCount[T any, K comparable](items []T, keyFn func(T) K) map[K]int {
+	counts := make(map[K]int)
+	for _, item := range items {
+		counts[keyFn(item)]++
+	}
+	return counts
+}
+
+// ResultGroupedBy runs the transformer and groups its final items with GroupBy
+func ResultGroupedBy[T, R any, K comparable](t *Transformer[T, R], keyFn func(R) K) (map[K][]R, error) {
+	groups := make(map[K][]R)
+	err := t.stream(context.Background(), func(item R) error {
+		key := keyFn(item)
+		groups[key] = append(groups[key], item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
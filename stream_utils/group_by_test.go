@@ -0,0 +1,37 @@
+package stream_utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot"}
+	firstLetter := func(word string) byte { return word[0] }
+
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado", "apricot"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, GroupBy(words, firstLetter))
+
+	assert.Equal(t, map[byte]int{'a': 3, 'b': 2, 'c': 1}, GroupByCount(words, firstLetter))
+	assert.Empty(t, GroupBy([]string{}, firstLetter))
+}
+
+func TestResultGroupedBy(t *testing.T) {
+	transformer := NewTransformer[string, string]([]string{"Go", "rust", "GLEAM", "ruby", ""}).
+		Transform(FilterItSimple(func(item string) bool { return item != "" })).
+		Transform(MapItSimple(strings.ToLower))
+
+	groups, err := ResultGroupedBy(transformer, func(item string) int { return len(item) })
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]string{2: {"go"}, 4: {"rust", "ruby"}, 5: {"gleam"}}, groups)
+
+	_, err = ResultGroupedBy(NewTransformer[string, string]([]string{"x"}).
+		Transform(MapIt(func(item string) (string, error) { return "", ErrTest })),
+		func(item string) string { return item })
+	assert.ErrorIs(t, err, ErrTest)
+}
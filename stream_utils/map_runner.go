@@ -1,24 +1,92 @@
 package stream_utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
 )
 
 type MappingFn[T any, R any] func(item T) (R, error)
 type FilterFn[T any] func(item T) (bool, error)
 type SimpleMapper[T any, R any] func(item T) R
 type SimpleFilter[T any] func(item T) bool
+type ContextMappingFn[T any, R any] func(ctx context.Context, item T) (R, error)
+type RecoverFn[T any, R any] func(item T, err error) (R, bool, error)
 
 type ObjectMapper interface {
 	Result(items any) (any, error)
 }
 
+// ContextObjectMapper is implemented by mappers that can observe the context
+// passed to Transformer.ResultCtx
+type ContextObjectMapper interface {
+	ObjectMapper
+	ResultCtx(ctx context.Context, items any) (any, error)
+}
+
+// hookedMapper is implemented by mappers that can report the items their filters drop
+// and skip failing items
+type hookedMapper interface {
+	resultWithHooks(ctx context.Context, items any, hooks stageHooks) (any, error)
+}
+
+// stageHooks observes a single stage run
+type stageHooks struct {
+	// reject is called for every item dropped by a filter
+	reject func(item any)
+	// itemErr is set when errors are collected, failing items are reported to it and skipped
+	itemErr func(index int, err error)
+}
+
+// ItemError is an item that failed while the chain collects errors.
+// Stage is the 1-based position of the stage in the chain, Index the 0-based position of the item in the stage input.
+type ItemError struct {
+	Stage int
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("stage %d item %d: %v", e.Stage, e.Index, e.Err)
+}
+
+// Unwrap returns the original item error so errors.Is and errors.As work
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// RejectedItem is an item dropped by a filter, Stage is the 1-based position of the filter in the chain
+type RejectedItem struct {
+	Stage int
+	Item  any
+}
+
 type MapRunner[T, R any] struct {
 	mappingFn    MappingFn[T, R]
+	ctxMappingFn ContextMappingFn[T, R]
+	// indexedFn maps an item along with its 0-based position in the stage input
+	indexedFn func(i int, item T) (R, error)
+	// optionalFn maps an item and reports whether the result should be kept
+	optionalFn func(ctx context.Context, item T) (R, bool, error)
 	filterFn     FilterFn[T]
 	simpleMapper SimpleMapper[T, R]
 	simpleFilter SimpleFilter[T]
+	// concurrency is the number of workers running ctxMappingFn, set by MapItParallel
+	concurrency int
+	// sliceFn maps the whole slice at once, for stages such as SortBy that need every item
+	sliceFn func(ctx context.Context, items []T) ([]R, error)
+	// newRunFilter builds the filter of a single run for stages such as TakeIt, which keep an item
+	// depending on its 0-based position in the stage input or on the items before it
+	newRunFilter func() func(i int, item T) bool
+	// runFilter is the filter built by newRunFilter for the current run
+	runFilter func(i int, item T) bool
+	// exhausted reports whether no item from position seen of the stage input on can pass the stage
+	exhausted func(seen int) bool
 	err          error
 }
 
@@ -29,6 +97,66 @@ func MapIt[T, R any](fn MappingFn[T, R]) *MapRunner[T, R] {
 	}
 }
 
+// MapItCtx is like MapIt but fn receives the context given to Transformer.ResultCtx,
+// so mappings doing IO can honor cancellation and deadlines.
+// When the chain is run with Result the context is context.Background().
+func MapItCtx[T, R any](fn ContextMappingFn[T, R]) *MapRunner[T, R] {
+	return &MapRunner[T, R]{
+		ctxMappingFn: fn,
+		err:          nil,
+	}
+}
+
+// MapItParallel is like MapIt but maps up to concurrency items at once on a pool of goroutines.
+// The output keeps the order of the input. The first error returned by fn stops the
+// remaining items from being started and fails the chain.
+func MapItParallel[T, R any](fn MappingFn[T, R], concurrency int) *MapRunner[T, R] {
+	return &MapRunner[T, R]{
+		ctxMappingFn: func(ctx context.Context, item T) (R, error) {
+			return fn(item)
+		},
+		concurrency: concurrency,
+		err:         nil,
+	}
+}
+
+// MapItRetry is like MapIt but retries fn for each item up to attempts times,
+// waiting backoff between attempts. The chain only fails once an item has
+// exhausted its attempts, with the last error returned by fn.
+// When run through ResultCtx, cancelling the context stops the retries.
+func MapItRetry[T, R any](fn MappingFn[T, R], attempts int, backoff time.Duration) *MapRunner[T, R] {
+	policy := retry.RetryPolicy{MaxAttempts: attempts, Backoff: retry.Constant(backoff)}
+	return MapItCtx(func(ctx context.Context, item T) (R, error) {
+		return retry.Retry(ctx, policy, func() (R, error) {
+			return fn(item)
+		})
+	})
+}
+
+// MapItRecover is like MapIt but hands every error of fn to recoverFn instead of
+// failing the chain. recoverFn can replace the error with a value (keep=true),
+// skip the item (keep=false) or return a new error which stops the chain.
+func MapItRecover[T, R any](fn MappingFn[T, R], recoverFn RecoverFn[T, R]) *MapRunner[T, R] {
+	return &MapRunner[T, R]{
+		optionalFn: func(ctx context.Context, item T) (R, bool, error) {
+			res, err := fn(item)
+			if err != nil {
+				return recoverFn(item, err)
+			}
+			return res, true, nil
+		},
+		err: nil,
+	}
+}
+
+// IndexedMapIt is like MapIt but fn also receives the 0-based position of the item in the stage input
+func IndexedMapIt[T, R any](fn func(i int, item T) (R, error)) *MapRunner[T, R] {
+	return &MapRunner[T, R]{
+		indexedFn: fn,
+		err:       nil,
+	}
+}
+
 func MapItSimple[T, R any](fn SimpleMapper[T, R]) *MapRunner[T, R] {
 	return &MapRunner[T, R]{
 		simpleMapper: fn,
@@ -51,46 +179,197 @@ func FilterItSimple[T any](fn SimpleFilter[T]) *MapRunner[T, T] {
 }
 
 func (m *MapRunner[T, R]) Result(items any) (any, error) {
+	return m.ResultCtx(context.Background(), items)
+}
+
+// ResultCtx runs the mapper over items, stopping with the context error once ctx is done
+func (m *MapRunner[T, R]) ResultCtx(ctx context.Context, items any) (any, error) {
+	return m.resultWithHooks(ctx, items, stageHooks{})
+}
+
+// resultWithHooks runs the mapper, reporting dropped and, when hooks.itemErr is set, failing items
+func (m *MapRunner[T, R]) resultWithHooks(ctx context.Context, items any, hooks stageHooks) (any, error) {
 	var results []R
 	if _, ok := items.([]T); !ok {
-		var t T
-		return nil, fmt.Errorf("not able to typecast items : %v", reflect.TypeOf(t).Name())
+		return nil, fmt.Errorf("not able to typecast items: mapper expects []%v, got %T", typeOf[T](), items)
 	}
-	for _, item := range (items).([]T) {
-		if m.mappingFn != nil {
-			res, err := m.mappingFn(item)
-			if err != nil {
-				return nil, err
-			}
-			results = append(results, res)
-		} else if m.filterFn != nil {
-			ok, err := m.filterFn(item)
-			if err != nil {
+	if m.sliceFn != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return m.sliceFn(ctx, items.([]T))
+	}
+	if m.concurrency > 1 && m.ctxMappingFn != nil {
+		return m.resultParallel(ctx, items.([]T), hooks)
+	}
+	if m.newRunFilter != nil {
+		m = m.forRun().(*MapRunner[T, R])
+	}
+	for i, item := range (items).([]T) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if m.isExhausted(i) {
+			break
+		}
+		res, keep, err := m.mapOne(ctx, i, item, hooks.reject)
+		if err != nil {
+			if hooks.itemErr == nil {
 				return nil, err
 			}
-			if ok {
-				var res any
-				res = item
-				results = append(results, res.(R))
-			}
-		} else if m.simpleMapper != nil {
-			res := m.simpleMapper(item)
+			hooks.itemErr(i, err)
+			continue
+		}
+		if keep {
 			results = append(results, res)
-		} else if m.simpleFilter != nil {
-			ok := m.simpleFilter(item)
-			if ok {
-				var res any
-				res = item
-				results = append(results, res.(R))
-			}
 		}
 	}
 	return results, nil
 }
 
+// mapOne runs the mapper on the item at position i, keep is false when a filter drops the item
+func (m *MapRunner[T, R]) mapOne(ctx context.Context, i int, item T, reject func(item any)) (res R, keep bool, err error) {
+	if m.indexedFn != nil {
+		res, err = m.indexedFn(i, item)
+		return res, err == nil, err
+	} else if m.optionalFn != nil {
+		return m.optionalFn(ctx, item)
+	} else if m.ctxMappingFn != nil {
+		res, err = m.ctxMappingFn(ctx, item)
+		return res, err == nil, err
+	} else if m.mappingFn != nil {
+		res, err = m.mappingFn(item)
+		return res, err == nil, err
+	} else if m.filterFn != nil {
+		if keep, err = m.filterFn(item); err != nil {
+			return res, false, err
+		}
+	} else if m.simpleMapper != nil {
+		return m.simpleMapper(item), true, nil
+	} else if m.simpleFilter != nil {
+		keep = m.simpleFilter(item)
+	} else if m.runFilter != nil {
+		// Position based drops are not filter rejections, so they are not reported
+		if !m.runFilter(i, item) {
+			return res, false, nil
+		}
+		keep = true
+	}
+
+	if !keep {
+		if reject != nil {
+			reject(item)
+		}
+		return res, false, nil
+	}
+	var kept any
+	kept = item
+	return kept.(R), true, nil
+}
+
+// mapItem runs the mapper on the item at position i of a channel source
+func (m *MapRunner[T, R]) mapItem(ctx context.Context, i int, item any, reject func(item any)) (any, bool, error) {
+	typed, ok := item.(T)
+	if !ok {
+		return nil, false, fmt.Errorf("not able to typecast item: mapper expects %v, got %T", typeOf[T](), item)
+	}
+	return m.mapOne(ctx, i, typed, reject)
+}
+
+// mapsItems reports whether the mapper can run one item at a time
+func (m *MapRunner[T, R]) mapsItems() bool {
+	return m.sliceFn == nil
+}
+
+// forRun returns the mapper to use for one run of the chain, with its own filter state
+func (m *MapRunner[T, R]) forRun() itemMapper {
+	if m.newRunFilter == nil {
+		return m
+	}
+	run := *m
+	run.runFilter, run.newRunFilter = m.newRunFilter(), nil
+	return &run
+}
+
+// isExhausted reports whether the stage drops every item from position seen of its input on
+func (m *MapRunner[T, R]) isExhausted(seen int) bool {
+	return m.exhausted != nil && m.exhausted(seen)
+}
+
+// resultParallel maps items with ctxMappingFn on m.concurrency workers, keeping their order.
+// When hooks.itemErr is set failing items are skipped instead of stopping the workers.
+func (m *MapRunner[T, R]) resultParallel(ctx context.Context, items []T, hooks stageHooks) (any, error) {
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	itemErrs := make([]error, len(items))
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < min(m.concurrency, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				res, err := m.ctxMappingFn(workerCtx, items[i])
+				if err != nil && hooks.itemErr != nil {
+					itemErrs[i] = err
+					continue
+				}
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = res
+			}
+		}()
+	}
+
+feed:
+	for i := range items {
+		select {
+		case <-workerCtx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if hooks.itemErr == nil {
+		return results, nil
+	}
+
+	kept := results[:0]
+	for i, res := range results {
+		if itemErrs[i] != nil {
+			hooks.itemErr(i, itemErrs[i])
+			continue
+		}
+		kept = append(kept, res)
+	}
+	return kept, nil
+}
+
 type Transformer[T any, R any] struct {
-	items   any
-	mappers []ObjectMapper
+	items         any
+	mappers       []ObjectMapper
+	collectErrors bool
+	// source is set by FromChannel, items are read from it when the chain runs
+	source <-chan T
 }
 
 func NewTransformer[T, R any](items []T) *Transformer[T, R] {
@@ -104,9 +383,97 @@ func (t *Transformer[T, R]) Transform(mapper ObjectMapper) *Transformer[T, R] {
 	return t
 }
 
+// CollectErrors keeps the chain running when an item fails to map or filter.
+// Failing items are skipped and the result holds the remaining items along with
+// every failure joined into one error, each wrapped in an ItemError.
+func (t *Transformer[T, R]) CollectErrors() *Transformer[T, R] {
+	t.collectErrors = true
+	return t
+}
+
+// Validate checks that the chain is wired with compatible types without processing any data.
+// Every stage is run against an empty []T, so no mapping or filter functions are called,
+// and the output of the last stage must be a []R.
+func (t *Transformer[T, R]) Validate() error {
+	var items any = []T{}
+	for i, mapper := range t.mappers {
+		out, err := mapper.Result(items)
+		if err != nil {
+			return fmt.Errorf("stage %d does not accept %T: %w", i+1, items, err)
+		}
+		items = out
+	}
+
+	if _, ok := items.([]R); !ok {
+		return fmt.Errorf("chain produces %T, expected []%v", items, typeOf[R]())
+	}
+	return nil
+}
+
 func (t *Transformer[T, R]) Result() (r []R, err error) {
-	for _, mapper := range t.mappers {
-		items, err := mapper.Result(t.items)
+	return t.ResultCtx(context.Background())
+}
+
+// ResultCtx runs the chain like Result, threading ctx through to context aware
+// mappers such as MapItCtx. The chain stops with the context error once ctx is done.
+func (t *Transformer[T, R]) ResultCtx(ctx context.Context) (r []R, err error) {
+	return t.run(ctx, nil)
+}
+
+// ResultWithRejected runs the chain like Result and also returns every item
+// dropped by a FilterIt or FilterItSimple stage, tagged with that stage.
+func (t *Transformer[T, R]) ResultWithRejected() (kept []R, rejected []RejectedItem, err error) {
+	kept, err = t.run(context.Background(), func(stage int, item any) {
+		rejected = append(rejected, RejectedItem{Stage: stage, Item: item})
+	})
+	return kept, rejected, err
+}
+
+// run executes every stage in order, reporting filtered items to onReject when it is set.
+// A FromChannel source is read lazily unless a stage needs every item.
+func (t *Transformer[T, R]) run(ctx context.Context, onReject func(stage int, item any)) (r []R, err error) {
+	if t.source != nil {
+		if stages, err := t.itemStages(); err == nil {
+			var results []R
+			itemErrs, err := t.streamSource(ctx, stages, onReject, func(item R) error {
+				results = append(results, item)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			return results, errors.Join(itemErrs...)
+		}
+		items, err := drain(ctx, t.source)
+		if err != nil {
+			return nil, err
+		}
+		t.items, t.source = items, nil
+	}
+
+	var itemErrs []error
+	for i, mapper := range t.mappers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var items any
+		if hooked, ok := mapper.(hookedMapper); ok && (onReject != nil || t.collectErrors) {
+			stage := i + 1
+			var hooks stageHooks
+			if onReject != nil {
+				hooks.reject = func(item any) { onReject(stage, item) }
+			}
+			if t.collectErrors {
+				hooks.itemErr = func(index int, err error) {
+					itemErrs = append(itemErrs, &ItemError{Stage: stage, Index: index, Err: err})
+				}
+			}
+			items, err = hooked.resultWithHooks(ctx, t.items, hooks)
+		} else if ctxMapper, ok := mapper.(ContextObjectMapper); ok {
+			items, err = ctxMapper.ResultCtx(ctx, t.items)
+		} else {
+			items, err = mapper.Result(t.items)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -114,8 +481,12 @@ func (t *Transformer[T, R]) Result() (r []R, err error) {
 	}
 
 	if _, ok := t.items.([]R); !ok {
-		var r R
-		return nil, fmt.Errorf("bad type casting %v", reflect.TypeOf(r).Name())
+		return nil, fmt.Errorf("bad type casting: chain produces %T, expected []%v", t.items, typeOf[R]())
 	}
-	return t.items.([]R), nil
+	return t.items.([]R), errors.Join(itemErrs...)
+}
+
+// typeOf returns the type T, including interface types that reflect.TypeOf reports as nil
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
 }
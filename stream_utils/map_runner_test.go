@@ -1,9 +1,13 @@
 package stream_utils
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -96,3 +100,295 @@ func TestMapItSimple(t *testing.T) {
 	t.Logf("Result: %v", res)
 	assert.ElementsMatch(t, []any{float64(1), float64(2), float64(220), float64(221)}, res)
 }
+
+func TestMapItRetry(t *testing.T) {
+	calls := map[string]int{}
+	flaky := func(item string) (float64, error) {
+		calls[item]++
+		if calls[item] < 3 {
+			return 0, ErrTest
+		}
+		return strconv.ParseFloat(item, 64)
+	}
+
+	res, err := NewTransformer[string, float64]([]string{"0.1", "22"}).
+		Transform(MapItRetry(flaky, 3, time.Millisecond)).
+		Result()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []float64{0.1, 22}, res)
+	assert.Equal(t, 3, calls["0.1"])
+	assert.Equal(t, 3, calls["22"])
+}
+
+func TestMapItRetryExhausted(t *testing.T) {
+	calls := 0
+	alwaysFails := func(item string) (float64, error) {
+		calls++
+		return 0, ErrTest
+	}
+
+	_, err := NewTransformer[string, float64]([]string{"0.1", "22"}).
+		Transform(MapItRetry(alwaysFails, 2, 0)).
+		Result()
+	assert.Equal(t, ErrTest, err)
+	// The chain stops after the first item exhausts its attempts
+	assert.Equal(t, 2, calls)
+}
+
+type ctxKey struct{}
+
+func TestMapItCtx(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, 10.0)
+
+	res, err := NewTransformer[string, float64]([]string{"1", "2.5"}).
+		Transform(MapIt[string, float64](func(item string) (float64, error) { return strconv.ParseFloat(item, 64) })).
+		Transform(MapItCtx(func(ctx context.Context, item float64) (float64, error) {
+			return item * ctx.Value(ctxKey{}).(float64), nil
+		})).
+		ResultCtx(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{10, 25}, res)
+}
+
+func TestMapItCtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	_, err := NewTransformer[int, int]([]int{1, 2, 3}).
+		Transform(MapItCtx(func(ctx context.Context, item int) (int, error) {
+			calls++
+			cancel()
+			return item, nil
+		})).
+		ResultCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMapItRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	_, err := NewTransformer[string, float64]([]string{"0.1"}).
+		Transform(MapItRetry(func(item string) (float64, error) {
+			calls++
+			cancel()
+			return 0, ErrTest
+		}, 5, time.Hour)).
+		ResultCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTransformerValidate(t *testing.T) {
+	calls := 0
+	parse := func(item string) (float64, error) {
+		calls++
+		return strconv.ParseFloat(item, 64)
+	}
+
+	t.Run("valid chain", func(t *testing.T) {
+		transformer := NewTransformer[string, int64]([]string{"1.5"}).
+			Transform(MapIt[string, float64](parse)).
+			Transform(MapItSimple[float64, int64](func(item float64) int64 { return int64(item) }))
+		assert.NoError(t, transformer.Validate())
+		assert.Equal(t, 0, calls)
+
+		// Validate leaves the data untouched
+		res, err := transformer.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+	})
+
+	t.Run("mismatched stage", func(t *testing.T) {
+		err := NewTransformer[string, int64]([]string{"1.5"}).
+			Transform(MapIt[string, float64](parse)).
+			Transform(FilterItSimple[int64](func(item int64) bool { return true })).
+			Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "stage 2")
+	})
+
+	t.Run("mismatched result", func(t *testing.T) {
+		err := NewTransformer[string, int64]([]string{"1.5"}).
+			Transform(MapIt[string, float64](parse)).
+			Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected []int64")
+	})
+}
+
+var errNotFound = errors.New("not found")
+
+func TestMapItRecover(t *testing.T) {
+	lookup := func(item string) (int, error) {
+		switch item {
+		case "missing":
+			return 0, errNotFound
+		case "broken":
+			return 0, ErrTest
+		}
+		return len(item), nil
+	}
+	recoverNotFound := func(item string, err error) (int, bool, error) {
+		if errors.Is(err, errNotFound) {
+			return 0, false, nil
+		}
+		if item == "broken" {
+			return -1, true, nil
+		}
+		return 0, false, err
+	}
+
+	res, err := NewTransformer[string, int]([]string{"a", "missing", "abc", "broken"}).
+		Transform(MapItRecover(lookup, recoverNotFound)).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, -1}, res)
+
+	_, err = NewTransformer[string, int]([]string{"a", "missing"}).
+		Transform(MapItRecover(lookup, func(item string, err error) (int, bool, error) {
+			return 0, false, fmt.Errorf("lookup %s: %w", item, err)
+		})).
+		Result()
+	assert.ErrorIs(t, err, errNotFound)
+	assert.EqualError(t, err, "lookup missing: not found")
+}
+
+func TestResultWithRejected(t *testing.T) {
+	floatingStrings := []string{"0.1", "0.2", "22", "22.1", "-5"}
+
+	kept, rejected, err := NewTransformer[string, int64](floatingStrings).
+		Transform(MapIt[string, float64](func(item string) (float64, error) { return strconv.ParseFloat(item, 64) })).
+		Transform(FilterItSimple[float64](func(item float64) bool { return item >= 0 })).
+		Transform(MapItSimple[float64, int64](func(item float64) int64 { return int64(item * 10) })).
+		Transform(FilterIt[int64](func(item int64) (bool, error) { return item%2 == 0, nil })).
+		ResultWithRejected()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 220}, kept)
+	assert.Equal(t, []RejectedItem{
+		{Stage: 2, Item: float64(-5)},
+		{Stage: 4, Item: int64(1)},
+		{Stage: 4, Item: int64(221)},
+	}, rejected)
+}
+
+func TestMapItParallel(t *testing.T) {
+	t.Run("keeps input order", func(t *testing.T) {
+		items := make([]int, 50)
+		for i := range items {
+			items[i] = i
+		}
+		var running, maxRunning atomic.Int32
+
+		res, err := NewTransformer[int, string](items).
+			Transform(MapItParallel(func(item int) (string, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					max := maxRunning.Load()
+					if n <= max || maxRunning.CompareAndSwap(max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Duration(50-item) * 10 * time.Microsecond)
+				return strconv.Itoa(item), nil
+			}, 4)).
+			Result()
+		assert.NoError(t, err)
+		assert.Len(t, res, 50)
+		for i, s := range res {
+			assert.Equal(t, strconv.Itoa(i), s)
+		}
+		assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+	})
+
+	t.Run("first error stops the chain", func(t *testing.T) {
+		var calls atomic.Int32
+		_, err := NewTransformer[int, int]([]int{1, 2, 3, 4, 5, 6, 7, 8}).
+			Transform(MapItParallel(func(item int) (int, error) {
+				calls.Add(1)
+				if item == 1 {
+					return 0, ErrTest
+				}
+				time.Sleep(5 * time.Millisecond)
+				return item, nil
+			}, 2)).
+			Result()
+		assert.ErrorIs(t, err, ErrTest)
+		assert.Less(t, calls.Load(), int32(8))
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		res, err := NewTransformer[int, int]([]int{}).
+			Transform(MapItParallel(func(item int) (int, error) { return item, nil }, 3)).
+			Result()
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestTransformerCollectErrors(t *testing.T) {
+	t.Run("skips failing items and joins their errors", func(t *testing.T) {
+		res, err := NewTransformer[string, int]([]string{"1", "x", "3", "y"}).
+			CollectErrors().
+			Transform(MapIt[string, int](strconv.Atoi)).
+			Result()
+		assert.Equal(t, []int{1, 3}, res)
+
+		var numErr *strconv.NumError
+		assert.ErrorAs(t, err, &numErr)
+		assert.Contains(t, err.Error(), `stage 1 item 1: strconv.Atoi: parsing "x"`)
+		assert.Contains(t, err.Error(), `stage 1 item 3: strconv.Atoi: parsing "y"`)
+	})
+
+	t.Run("parallel stage", func(t *testing.T) {
+		res, err := NewTransformer[int, int]([]int{1, 2, 3, 4}).
+			CollectErrors().
+			Transform(MapItParallel(func(item int) (int, error) {
+				if item%2 == 0 {
+					return 0, ErrTest
+				}
+				return item * 10, nil
+			}, 2)).
+			Result()
+		assert.Equal(t, []int{10, 30}, res)
+		assert.ErrorIs(t, err, ErrTest)
+		assert.Contains(t, err.Error(), "stage 1 item 1")
+		assert.Contains(t, err.Error(), "stage 1 item 3")
+	})
+
+	t.Run("no failures", func(t *testing.T) {
+		res, err := NewTransformer[int, int]([]int{1, 2}).
+			CollectErrors().
+			Transform(MapItSimple(func(item int) int { return item + 1 })).
+			Result()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 3}, res)
+	})
+}
+
+func TestIndexedMapIt(t *testing.T) {
+	res, err := NewTransformer[string, string]([]string{"a", "b", "c"}).
+		Transform(IndexedMapIt(func(i int, item string) (string, error) {
+			return strconv.Itoa(i) + ":" + item, nil
+		})).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0:a", "1:b", "2:c"}, res)
+
+	// The index is the position in the stage input, after earlier filters
+	res, err = NewTransformer[string, string]([]string{"a", "", "b"}).
+		Transform(FilterItSimple(func(item string) bool { return item != "" })).
+		Transform(IndexedMapIt(func(i int, item string) (string, error) {
+			return strconv.Itoa(i) + ":" + item, nil
+		})).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0:a", "1:b"}, res)
+
+	_, err = NewTransformer[string, string]([]string{"a"}).
+		Transform(IndexedMapIt(func(i int, item string) (string, error) { return "", ErrTest })).
+		Result()
+	assert.ErrorIs(t, err, ErrTest)
+}
@@ -0,0 +1,66 @@
+package stream_utils
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// ndjsonFlushEvery is how many items are written between flushes of a flushable writer
+const ndjsonFlushEvery = 100
+
+// flusher is implemented by writers such as http.ResponseWriter that buffer output
+type flusher interface {
+	Flush()
+}
+
+// ResultToNDJSON runs the chain and writes every resulting item to w as one line of JSON.
+// Writers implementing Flush, like http.ResponseWriter, are flushed periodically and at the end.
+func (t *Transformer[T, R]) ResultToNDJSON(w io.Writer) error {
+	return t.ResultToNDJSONCtx(context.Background(), w)
+}
+
+// ResultToNDJSONCtx is like ResultToNDJSON but stops with the context error once ctx is done.
+// Items are encoded as the chain emits them, so with a FromChannel source nothing is buffered
+// beyond the item being written, which suits large exports such as the output of dbutils.ScanRowsChan.
+// The error of the chain is returned once the items written before it are flushed.
+func (t *Transformer[T, R]) ResultToNDJSONCtx(ctx context.Context, w io.Writer) error {
+	encoder := newNDJSONEncoder(w)
+	defer encoder.flush()
+	return t.stream(ctx, func(item R) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return encoder.encode(item)
+	})
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+	flusher flusher
+	pending int
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	f, _ := w.(flusher)
+	return &ndjsonEncoder{encoder: json.NewEncoder(w), flusher: f}
+}
+
+// encode writes item followed by a newline, flushing every ndjsonFlushEvery items
+func (e *ndjsonEncoder) encode(item any) error {
+	if err := e.encoder.Encode(item); err != nil {
+		return err
+	}
+	e.pending++
+	if e.pending >= ndjsonFlushEvery {
+		e.flush()
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder) flush() {
+	if e.flusher != nil && e.pending > 0 {
+		e.flusher.Flush()
+	}
+	e.pending = 0
+}
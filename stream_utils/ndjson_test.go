@@ -0,0 +1,69 @@
+package stream_utils
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ndjsonItem struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func TestResultToNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewTransformer[string, ndjsonItem]([]string{"1", "2"}).
+		Transform(MapIt[string, ndjsonItem](func(item string) (ndjsonItem, error) {
+			v, err := strconv.Atoi(item)
+			return ndjsonItem{Name: "item" + item, Value: v}, err
+		})).
+		ResultToNDJSON(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"item1\",\"value\":1}\n{\"name\":\"item2\",\"value\":2}\n", buf.String())
+
+	err = NewTransformer[string, ndjsonItem]([]string{"x"}).
+		Transform(MapIt[string, ndjsonItem](func(item string) (ndjsonItem, error) { return ndjsonItem{}, ErrTest })).
+		ResultToNDJSON(&buf)
+	assert.Equal(t, ErrTest, err)
+}
+
+func TestResultToNDJSONFromChannel(t *testing.T) {
+	items := make(chan int)
+	go func() {
+		defer close(items)
+		for i := 0; i < 250; i++ {
+			items <- i
+		}
+	}()
+
+	recorder := httptest.NewRecorder()
+	err := FromChannel[int, int](items).ResultToNDJSONCtx(context.Background(), recorder)
+	assert.NoError(t, err)
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, 250, bytes.Count(recorder.Body.Bytes(), []byte("\n")))
+	assert.True(t, bytes.HasPrefix(recorder.Body.Bytes(), []byte("0\n1\n2\n")))
+}
+
+func TestResultToNDJSONFromChannelError(t *testing.T) {
+	var buf bytes.Buffer
+	err := FromChannel[string, int](produce("1", "2", "x", "4")).
+		Transform(MapIt[string, int](strconv.Atoi)).
+		ResultToNDJSONCtx(context.Background(), &buf)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Equal(t, "1\n2\n", buf.String())
+}
+
+func TestResultToNDJSONCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	err := FromChannel[int, int](make(chan int)).ResultToNDJSONCtx(ctx, &buf)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,40 @@
+package stream_utils
+
+import "context"
+
+// Partition splits items into the ones matching pred and the rest, both in input order.
+// It stops at the first error returned by pred.
+func Partition[T any](items []T, pred FilterFn[T]) (matched []T, rest []T, err error) {
+	for _, item := range items {
+		ok, err := pred(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return matched, rest, nil
+}
+
+// ResultPartitioned runs the chain like Result and splits its final items with Partition
+func (t *Transformer[T, R]) ResultPartitioned(pred FilterFn[R]) (matched []R, rest []R, err error) {
+	err = t.stream(context.Background(), func(item R) error {
+		ok, err := pred(item)
+		if err != nil {
+			return err
+		}
+		if ok {
+			matched = append(matched, item)
+		} else {
+			rest = append(rest, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return matched, rest, nil
+}
@@ -0,0 +1,46 @@
+package stream_utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartition(t *testing.T) {
+	t.Run("keeps order on both sides", func(t *testing.T) {
+		matched, rest, err := Partition([]int{5, 2, 8, 1, 4}, func(item int) (bool, error) { return item%2 == 0, nil })
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 8, 4}, matched)
+		assert.Equal(t, []int{5, 1}, rest)
+	})
+
+	t.Run("stops on predicate error", func(t *testing.T) {
+		calls := 0
+		matched, rest, err := Partition([]int{1, 2, 3}, func(item int) (bool, error) {
+			calls++
+			if item == 2 {
+				return false, ErrTest
+			}
+			return true, nil
+		})
+		assert.ErrorIs(t, err, ErrTest)
+		assert.Nil(t, matched)
+		assert.Nil(t, rest)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestResultPartitioned(t *testing.T) {
+	matched, rest, err := NewTransformer[string, int]([]string{"3", "10", "7", "12"}).
+		Transform(MapIt[string, int](strconv.Atoi)).
+		ResultPartitioned(func(item int) (bool, error) { return item >= 10, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, 12}, matched)
+	assert.Equal(t, []int{3, 7}, rest)
+
+	_, _, err = NewTransformer[string, int]([]string{"x"}).
+		Transform(MapIt[string, int](strconv.Atoi)).
+		ResultPartitioned(func(item int) (bool, error) { return true, nil })
+	assert.Error(t, err)
+}
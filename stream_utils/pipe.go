@@ -0,0 +1,62 @@
+package stream_utils
+
+import (
+	"context"
+	"fmt"
+)
+
+// TypedMapper maps a typed slice, unlike ObjectMapper the element types are checked at compile time
+type TypedMapper[T, R any] interface {
+	Apply(ctx context.Context, items []T) ([]R, error)
+}
+
+// Apply runs the mapper over items with their element types checked at compile time
+func (m *MapRunner[T, R]) Apply(ctx context.Context, items []T) ([]R, error) {
+	res, err := m.ResultCtx(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]R), nil
+}
+
+// PipeRunner is a chain of mappers built with Pipe
+type PipeRunner[T, R any] struct {
+	apply func(ctx context.Context, items []T) ([]R, error)
+}
+
+// Pipe chains first and second into a single mapper. A second stage that does not accept
+// the output of the first does not compile, e.g.
+//
+//	chain := Pipe(Pipe(MapIt(parse), FilterItSimple(isPositive)), MapItSimple(format))
+//	out, err := chain.Apply(ctx, in)
+//
+// The result is also an ObjectMapper so it can be passed to Transformer.Transform.
+func Pipe[A, B, C any](first TypedMapper[A, B], second TypedMapper[B, C]) *PipeRunner[A, C] {
+	return &PipeRunner[A, C]{
+		apply: func(ctx context.Context, items []A) ([]C, error) {
+			mid, err := first.Apply(ctx, items)
+			if err != nil {
+				return nil, err
+			}
+			return second.Apply(ctx, mid)
+		},
+	}
+}
+
+// Apply runs every stage of the chain in order
+func (p *PipeRunner[T, R]) Apply(ctx context.Context, items []T) ([]R, error) {
+	return p.apply(ctx, items)
+}
+
+func (p *PipeRunner[T, R]) Result(items any) (any, error) {
+	return p.ResultCtx(context.Background(), items)
+}
+
+// ResultCtx runs the chain on items, which must be a []T
+func (p *PipeRunner[T, R]) ResultCtx(ctx context.Context, items any) (any, error) {
+	typed, ok := items.([]T)
+	if !ok {
+		return nil, fmt.Errorf("not able to typecast items: pipe expects []%v, got %T", typeOf[T](), items)
+	}
+	return p.apply(ctx, typed)
+}
@@ -0,0 +1,59 @@
+package stream_utils
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipe(t *testing.T) {
+	parse := MapIt(func(item string) (int, error) { return strconv.Atoi(item) })
+	positive := FilterItSimple(func(item int) bool { return item > 0 })
+	format := MapItSimple(func(item int) string { return "#" + strconv.Itoa(item) })
+
+	t.Run("typed chain", func(t *testing.T) {
+		chain := Pipe(Pipe(parse, positive), format)
+		out, err := chain.Apply(context.Background(), []string{"3", "-1", "7"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"#3", "#7"}, out)
+	})
+
+	t.Run("stage error", func(t *testing.T) {
+		_, err := Pipe(parse, format).Apply(context.Background(), []string{"x"})
+		assert.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+
+	t.Run("usable with Transform", func(t *testing.T) {
+		out, err := NewTransformer[string, string]([]string{"1", "2"}).
+			Transform(Pipe(parse, format)).
+			Result()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"#1", "#2"}, out)
+	})
+
+	t.Run("mis-chained pipe reports both types", func(t *testing.T) {
+		_, err := NewTransformer[int, string]([]int{1}).
+			Transform(Pipe(parse, format)).
+			Result()
+		assert.EqualError(t, err, "not able to typecast items: pipe expects []string, got []int")
+	})
+}
+
+func TestTransformerTypeErrors(t *testing.T) {
+	_, err := NewTransformer[int, string]([]int{1}).
+		Transform(MapItSimple(func(item string) string { return item })).
+		Result()
+	assert.EqualError(t, err, "not able to typecast items: mapper expects []string, got []int")
+
+	_, err = NewTransformer[int, string]([]int{1}).
+		Transform(MapItSimple(func(item int) int { return item })).
+		Result()
+	assert.EqualError(t, err, "bad type casting: chain produces []int, expected []string")
+
+	_, err = NewTransformer[int, any]([]int{1}).
+		Transform(MapItSimple(func(item int) int { return item })).
+		Result()
+	assert.EqualError(t, err, "bad type casting: chain produces []int, expected []interface {}")
+}
@@ -0,0 +1,77 @@
+package stream_utils
+
+import "context"
+
+// And returns a FilterFn that matches when every predicate matches.
+// It stops at the first predicate that does not match or returns an error.
+func And[T any](preds ...FilterFn[T]) FilterFn[T] {
+	return func(item T) (bool, error) {
+		for _, pred := range preds {
+			ok, err := pred(item)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// Or returns a FilterFn that matches when any predicate matches.
+// It stops at the first predicate that matches or returns an error.
+func Or[T any](preds ...FilterFn[T]) FilterFn[T] {
+	return func(item T) (bool, error) {
+		for _, pred := range preds {
+			ok, err := pred(item)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// Not negates a FilterFn, errors are passed through unchanged.
+func Not[T any](pred FilterFn[T]) FilterFn[T] {
+	return func(item T) (bool, error) {
+		ok, err := pred(item)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+}
+
+// AnyMatch runs the transformer and reports whether any final item matches pred.
+// It stops at the first match or predicate error, without reading the rest of a FromChannel source.
+func AnyMatch[T, R any](t *Transformer[T, R], pred FilterFn[R]) (bool, error) {
+	found := false
+	err := t.stream(context.Background(), func(item R) error {
+		ok, err := pred(item)
+		if err != nil {
+			return err
+		}
+		if ok {
+			found = true
+			return errStopStream
+		}
+		return nil
+	})
+	return found, err
+}
+
+// AllMatch runs the transformer and reports whether every final item matches pred, true for no items.
+// It stops at the first item that does not match or returns an error.
+func AllMatch[T, R any](t *Transformer[T, R], pred FilterFn[R]) (bool, error) {
+	found, err := AnyMatch(t, Not(pred))
+	return !found && err == nil, err
+}
+
+// NoneMatch runs the transformer and reports whether no final item matches pred.
+// It stops at the first match or predicate error.
+func NoneMatch[T, R any](t *Transformer[T, R], pred FilterFn[R]) (bool, error) {
+	found, err := AnyMatch(t, pred)
+	return !found && err == nil, err
+}
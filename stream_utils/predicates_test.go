@@ -0,0 +1,116 @@
+package stream_utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isPositive(item int64) (bool, error) { return item > 0, nil }
+func isEven(item int64) (bool, error)     { return item%2 == 0, nil }
+func failing(item int64) (bool, error)    { return false, ErrTest }
+
+func TestPredicateCombinators(t *testing.T) {
+	items := []int64{-4, -3, 0, 1, 2, 3, 4}
+
+	res, err := NewTransformer[int64, int64](items).
+		Transform(FilterIt(And(isPositive, isEven))).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, res)
+
+	res, err = NewTransformer[int64, int64](items).
+		Transform(FilterIt(Or(isPositive, isEven))).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{-4, 0, 1, 2, 3, 4}, res)
+
+	res, err = NewTransformer[int64, int64](items).
+		Transform(FilterIt(And(isPositive, Not(isEven)))).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, res)
+}
+
+func TestPredicateCombinatorsShortCircuit(t *testing.T) {
+	// failing is never reached once the result is decided
+	ok, err := And(isPositive, failing)(-1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = Or(isPositive, failing)(1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPredicateCombinatorsError(t *testing.T) {
+	_, err := NewTransformer[int64, int64]([]int64{1, 2}).
+		Transform(FilterIt(And(isPositive, failing))).
+		Result()
+	assert.Equal(t, ErrTest, err)
+
+	_, err = Or(failing, isPositive)(1)
+	assert.Equal(t, ErrTest, err)
+
+	_, err = Not(failing)(1)
+	assert.Equal(t, ErrTest, err)
+}
+
+func TestMatchTerminals(t *testing.T) {
+	items := []int64{1, 2, 3, 4}
+	stream := func() *Transformer[int64, int64] { return NewTransformer[int64, int64](items) }
+
+	t.Run("any match short circuits", func(t *testing.T) {
+		var seen []int64
+		ok, err := AnyMatch(stream(), func(item int64) (bool, error) {
+			seen = append(seen, item)
+			return isEven(item)
+		})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []int64{1, 2}, seen)
+	})
+
+	t.Run("all match short circuits", func(t *testing.T) {
+		var seen []int64
+		ok, err := AllMatch(stream(), func(item int64) (bool, error) {
+			seen = append(seen, item)
+			return item < 2, nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, []int64{1, 2}, seen)
+
+		ok, err = AllMatch(stream(), isPositive)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("none match", func(t *testing.T) {
+		ok, err := NoneMatch(stream(), func(item int64) (bool, error) { return item > 10, nil })
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = NoneMatch(stream(), isEven)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		empty := func() *Transformer[int64, int64] { return NewTransformer[int64, int64](nil) }
+		ok, _ := AnyMatch(empty(), isEven)
+		assert.False(t, ok)
+		ok, _ = AllMatch(empty(), isEven)
+		assert.True(t, ok)
+		ok, _ = NoneMatch(empty(), isEven)
+		assert.True(t, ok)
+	})
+
+	t.Run("predicate error propagates", func(t *testing.T) {
+		for _, match := range []func(*Transformer[int64, int64], FilterFn[int64]) (bool, error){AnyMatch, AllMatch, NoneMatch} {
+			ok, err := match(stream(), failing)
+			assert.ErrorIs(t, err, ErrTest)
+			assert.False(t, ok)
+		}
+	})
+}
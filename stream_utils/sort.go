@@ -0,0 +1,33 @@
+package stream_utils
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// SortBy orders the items with less. The sort is stable so equal items keep their order.
+// It buffers the whole slice, the input slice is left untouched.
+func SortBy[T any](less func(a, b T) bool) *MapRunner[T, T] {
+	return &MapRunner[T, T]{
+		sliceFn: func(ctx context.Context, items []T) ([]T, error) {
+			sorted := slices.Clone(items)
+			slices.SortStableFunc(sorted, func(a, b T) int {
+				if less(a, b) {
+					return -1
+				}
+				if less(b, a) {
+					return 1
+				}
+				return 0
+			})
+			return sorted, nil
+		},
+		err: nil,
+	}
+}
+
+// Sorted orders the items in ascending natural order
+func Sorted[T cmp.Ordered]() *MapRunner[T, T] {
+	return SortBy(cmp.Less[T])
+}
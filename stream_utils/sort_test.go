@@ -0,0 +1,35 @@
+package stream_utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSorted(t *testing.T) {
+	floatingStrings := []string{"22.1", "0.2", "22", "0.1"}
+
+	res, err := NewTransformer[string, float64](floatingStrings).
+		Transform(MapIt[string, float64](func(item string) (float64, error) { return strconv.ParseFloat(item, 64) })).
+		Transform(Sorted[float64]()).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.1, 0.2, 22, 22.1}, res)
+	assert.Equal(t, []string{"22.1", "0.2", "22", "0.1"}, floatingStrings)
+}
+
+func TestSortBy(t *testing.T) {
+	floatingStrings := []string{"0.1", "22", "0.2", "22.1"}
+
+	res, err := NewTransformer[string, string](floatingStrings).
+		Transform(SortBy(func(a, b string) bool {
+			fa, _ := strconv.ParseFloat(a, 64)
+			fb, _ := strconv.ParseFloat(b, 64)
+			return int(fa) > int(fb)
+		})).
+		Result()
+	assert.NoError(t, err)
+	// Stable: items with the same integer part keep their input order
+	assert.Equal(t, []string{"22", "22.1", "0.1", "0.2"}, res)
+}
@@ -0,0 +1,46 @@
+package stream_utils
+
+import (
+	"context"
+	"fmt"
+)
+
+// TakeIt keeps the first n items and drops the rest without looking at them.
+// On a FromChannel source no further item is read once n items went through.
+func TakeIt[T any](n int) *MapRunner[T, T] {
+	return &MapRunner[T, T]{
+		newRunFilter: func() func(i int, item T) bool {
+			return func(i int, item T) bool { return i < n }
+		},
+		exhausted: func(seen int) bool { return seen >= n },
+		err:       nil,
+	}
+}
+
+// SkipIt drops the first n items and keeps the rest
+func SkipIt[T any](n int) *MapRunner[T, T] {
+	return &MapRunner[T, T]{
+		newRunFilter: func() func(i int, item T) bool {
+			return func(i int, item T) bool { return i >= n }
+		},
+		err: nil,
+	}
+}
+
+// ChunkIt groups the items into slices of size items, the last chunk may be shorter
+func ChunkIt[T any](size int) *MapRunner[T, []T] {
+	return &MapRunner[T, []T]{
+		sliceFn: func(ctx context.Context, items []T) ([][]T, error) {
+			if size <= 0 {
+				return nil, fmt.Errorf("chunk size must be positive, got %d", size)
+			}
+			chunks := make([][]T, 0, (len(items)+size-1)/size)
+			for start := 0; start < len(items); start += size {
+				end := min(start+size, len(items))
+				chunks = append(chunks, items[start:end:end])
+			}
+			return chunks, nil
+		},
+		err: nil,
+	}
+}
@@ -0,0 +1,79 @@
+package stream_utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeIt(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	res, err := NewTransformer[int, int](items).Transform(TakeIt[int](3)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, res)
+
+	res, err = NewTransformer[int, int](items).Transform(TakeIt[int](10)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, items, res)
+
+	res, err = NewTransformer[int, int](items).Transform(TakeIt[int](-1)).Result()
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestSkipIt(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	res, err := NewTransformer[int, int](items).Transform(SkipIt[int](2)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, res)
+
+	res, err = NewTransformer[int, int](items).Transform(SkipIt[int](10)).Result()
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestTakeItFromChannel(t *testing.T) {
+	// The source never ends, so the chain only finishes if TakeIt stops reading it
+	src := make(chan int)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			case src <- i:
+			}
+		}
+	}()
+
+	out, errc := FromChannel[int, int](src).
+		Transform(SkipIt[int](2)).
+		Transform(TakeIt[int](3)).
+		ToChannel(context.Background())
+	res, err := collect(out, errc)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, res)
+}
+
+func TestChunkIt(t *testing.T) {
+	res, err := NewTransformer[int, []int]([]int{1, 2, 3, 4, 5}).Transform(ChunkIt[int](2)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, res)
+
+	_, err = NewTransformer[int, []int]([]int{1}).Transform(ChunkIt[int](0)).Result()
+	assert.EqualError(t, err, "chunk size must be positive, got 0")
+}
+
+func TestPagination(t *testing.T) {
+	pageSize, page := 2, 1
+	res, err := NewTransformer[int, int]([]int{10, 20, 30, 40, 50}).
+		Transform(SkipIt[int](page * pageSize)).
+		Transform(TakeIt[int](pageSize)).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{30, 40}, res)
+}
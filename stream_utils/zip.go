@@ -0,0 +1,47 @@
+package stream_utils
+
+import "fmt"
+
+// Pair holds two values taken from the same position of two slices
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs up the elements of a and b by position.
+// The result is as long as the shorter slice.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	pairs := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		pairs[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return pairs
+}
+
+// ZipErr combines the elements of a and b by position with fn.
+// It stops at the first failing pair and returns its error prefixed with the pair index.
+// Like Zip, only as many pairs as the shorter slice holds are combined.
+func ZipErr[A, B, R any](a []A, b []B, fn func(A, B) (R, error)) ([]R, error) {
+	n := min(len(a), len(b))
+	results := make([]R, 0, n)
+	for i := 0; i < n; i++ {
+		res, err := fn(a[i], b[i])
+		if err != nil {
+			return nil, fmt.Errorf("pair %d: %w", i, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Unzip splits pairs back into two slices
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, pair := range pairs {
+		a[i] = pair.First
+		b[i] = pair.Second
+	}
+	return a, b
+}
@@ -0,0 +1,39 @@
+package stream_utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZipUnzip(t *testing.T) {
+	pairs := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	assert.Equal(t, []Pair[string, int]{{"a", 1}, {"b", 2}}, pairs)
+
+	names, counts := Unzip(pairs)
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Equal(t, []int{1, 2}, counts)
+}
+
+func TestZipErr(t *testing.T) {
+	repeat := func(s string, n string) (string, error) {
+		count, err := strconv.Atoi(n)
+		if err != nil {
+			return "", err
+		}
+		res := ""
+		for i := 0; i < count; i++ {
+			res += s
+		}
+		return res, nil
+	}
+
+	res, err := ZipErr([]string{"a", "b"}, []string{"2", "3"}, repeat)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aa", "bbb"}, res)
+
+	_, err = ZipErr([]string{"a", "b"}, []string{"2", "x"}, repeat)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.ErrorContains(t, err, "pair 1")
+}
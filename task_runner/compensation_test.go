@@ -0,0 +1,71 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	steps []string
+}
+
+func step(name string, err error) TaskExecutor[order] {
+	return func(ctx context.Context, taskReq *order) error {
+		taskReq.steps = append(taskReq.steps, name)
+		return err
+	}
+}
+
+func TestThenWithCompensation(t *testing.T) {
+	t.Run("undoes completed steps in reverse order", func(t *testing.T) {
+		res, err := NewSimpleTaskRunner(context.TODO(), order{}).
+			ThenWithCompensation(step("charge", nil), step("refund", nil)).
+			Then(step("log", nil)).
+			ThenWithCompensation(step("reserve", nil), step("release", nil)).
+			ThenWithCompensation(step("ship", errFoo), step("unship", nil)).
+			Then(step("notify", nil)).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.Equal(t, []string{"charge", "log", "reserve", "ship", "release", "refund"}, res.steps)
+	})
+
+	t.Run("joins compensation errors", func(t *testing.T) {
+		errRefund := errors.New("refund failed")
+		res, err := NewSimpleTaskRunner(context.TODO(), order{}).
+			ThenWithCompensation(step("charge", nil), step("refund", errRefund)).
+			ThenWithCompensation(step("reserve", nil), step("release", nil)).
+			Then(step("ship", errFoo)).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.ErrorIs(t, err, errRefund)
+		assert.Equal(t, []string{"charge", "reserve", "ship", "release", "refund"}, res.steps)
+	})
+
+	t.Run("no compensation on success", func(t *testing.T) {
+		res, err := NewSimpleTaskRunner(context.TODO(), order{}).
+			ThenWithCompensation(step("charge", nil), step("refund", nil)).
+			Then(step("ship", nil)).
+			Result()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"charge", "ship"}, res.steps)
+	})
+
+	t.Run("runs on cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		res, err := NewSimpleTaskRunner(ctx, order{}).
+			ThenWithCompensation(step("charge", nil), func(ctx context.Context, taskReq *order) error {
+				taskReq.steps = append(taskReq.steps, "refund")
+				return ctx.Err()
+			}).
+			Then(func(ctx context.Context, taskReq *order) error {
+				cancel()
+				return ctx.Err()
+			}).
+			Result()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"charge", "refund"}, res.steps)
+	})
+}
@@ -0,0 +1,181 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mahadev-k/go-utils/collections"
+	"github.com/mahadev-k/go-utils/goctx"
+)
+
+// DependencyRunner runs tasks as a DAG: every task starts once all of its dependencies
+// have succeeded, so independent branches run in parallel. Like Parallel tasks of
+// SimpleTaskRunner, every task receives mu and must hold it to read or write the shared request.
+type DependencyRunner[T any] struct {
+	ctx     context.Context
+	taskReq T
+	// mu guards taskReq while tasks of the same level run concurrently
+	mu    sync.RWMutex
+	tasks map[string]dependencyTask[T]
+	// order keeps the tasks in the order they were added
+	order []string
+	err   error
+}
+
+type dependencyTask[T any] struct {
+	deps []string
+	exec ParallelExecutor[T]
+}
+
+func NewDependencyRunner[T any](ctx context.Context, taskReq T) *DependencyRunner[T] {
+	return &DependencyRunner[T]{
+		ctx:     ctx,
+		taskReq: taskReq,
+		tasks:   make(map[string]dependencyTask[T]),
+	}
+}
+
+// AddTask adds a task named name that runs after every task in deps.
+// Dependencies may be added later, adding a name twice makes Run fail.
+func (d *DependencyRunner[T]) AddTask(name string, deps []string, exec ParallelExecutor[T]) *DependencyRunner[T] {
+	if _, exists := d.tasks[name]; exists {
+		d.err = errors.Join(d.err, fmt.Errorf("task %q added twice", name))
+		return d
+	}
+	d.tasks[name] = dependencyTask[T]{deps: deps, exec: exec}
+	d.order = append(d.order, name)
+	return d
+}
+
+// Plan returns the tasks grouped by level: the first group has no dependencies and every
+// later group only depends on earlier ones, so the tasks of a group can run concurrently.
+// Names are sorted within a group. Unknown dependencies and cycles are reported as errors.
+func (d *DependencyRunner[T]) Plan() ([][]string, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	pending := make(map[string]int, len(d.tasks))
+	dependents := make(map[string][]string, len(d.tasks))
+	for _, name := range d.order {
+		for _, dep := range d.tasks[name].deps {
+			if _, ok := d.tasks[dep]; !ok {
+				return nil, fmt.Errorf("task %q depends on unknown task %q", name, dep)
+			}
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready collections.Queue[string]
+	for _, name := range d.order {
+		if pending[name] == 0 {
+			ready.Push(name)
+		}
+	}
+	var plan [][]string
+	planned := 0
+	for ready.Len() > 0 {
+		// Everything queued so far forms the next level, its dependents are queued behind it
+		level := make([]string, ready.Len())
+		for i := range level {
+			level[i], _ = ready.Pop()
+		}
+		sort.Strings(level)
+		plan = append(plan, level)
+		planned += len(level)
+
+		for _, name := range level {
+			for _, dependent := range dependents[name] {
+				if pending[dependent]--; pending[dependent] == 0 {
+					ready.Push(dependent)
+				}
+			}
+		}
+	}
+
+	if planned < len(d.tasks) {
+		var cyclic []string
+		for _, name := range d.order {
+			if pending[name] > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("dependency cycle between tasks %q", cyclic)
+	}
+	return plan, nil
+}
+
+// Run checks the graph with Plan and then runs every task once its dependencies succeeded.
+// Tasks depending on a failed task are skipped, independent tasks still run.
+// Task errors are joined in plan order, each prefixed with the task name, and once ctx is
+// done no new tasks start and the context error is returned alongside them.
+// Panics are returned as *goctx.PanicError.
+func (d *DependencyRunner[T]) Run() (T, error) {
+	plan, err := d.Plan()
+	if err != nil {
+		return d.taskReq, err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]bool, len(d.tasks))
+		errs   = make(map[string]error)
+	)
+	done := make(map[string]chan struct{}, len(d.tasks))
+	for _, name := range d.order {
+		done[name] = make(chan struct{})
+	}
+
+	for _, name := range d.order {
+		task := d.tasks[name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[name])
+			for _, dep := range task.deps {
+				<-done[dep]
+			}
+
+			mu.Lock()
+			skip := d.ctx.Err() != nil
+			for _, dep := range task.deps {
+				skip = skip || failed[dep]
+			}
+			if skip {
+				failed[name] = true
+			}
+			mu.Unlock()
+			if skip {
+				return
+			}
+
+			err := goctx.PanicRecoverAsError.Call(func() error {
+				return task.exec(d.ctx, &d.taskReq, &d.mu)
+			})
+			if err != nil {
+				mu.Lock()
+				failed[name] = true
+				errs[name] = fmt.Errorf("task %s: %w", name, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var joined []error
+	for _, level := range plan {
+		for _, name := range level {
+			joined = append(joined, errs[name])
+		}
+	}
+	if err := d.ctx.Err(); err != nil {
+		joined = append(joined, err)
+	}
+	return d.taskReq, errors.Join(joined...)
+}
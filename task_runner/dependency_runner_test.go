@@ -0,0 +1,137 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mahadev-k/go-utils/goctx"
+	"github.com/stretchr/testify/assert"
+)
+
+type checkout struct {
+	total int
+	steps []string
+}
+
+func record(name string, delay time.Duration, err error) ParallelExecutor[checkout] {
+	return func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error {
+		time.Sleep(delay)
+		mu.Lock()
+		defer mu.Unlock()
+		taskReq.steps = append(taskReq.steps, name)
+		return err
+	}
+}
+
+func TestDependencyRunnerPlan(t *testing.T) {
+	plan, err := NewDependencyRunner(context.TODO(), checkout{}).
+		AddTask("ship", []string{"payment", "inventory"}, nil).
+		AddTask("payment", []string{"validate"}, nil).
+		AddTask("inventory", []string{"validate"}, nil).
+		AddTask("validate", nil, nil).
+		AddTask("email", []string{"validate"}, nil).
+		Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"validate"}, {"email", "inventory", "payment"}, {"ship"}}, plan)
+}
+
+func TestDependencyRunnerInvalidGraph(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		ran := false
+		_, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("a", []string{"c"}, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error { ran = true; return nil }).
+			AddTask("b", []string{"a"}, nil).
+			AddTask("c", []string{"b"}, nil).
+			AddTask("d", nil, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error { ran = true; return nil }).
+			Run()
+		assert.EqualError(t, err, `dependency cycle between tasks ["a" "b" "c"]`)
+		assert.False(t, ran)
+	})
+
+	t.Run("unknown dependency", func(t *testing.T) {
+		_, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("ship", []string{"payment"}, nil).
+			Plan()
+		assert.EqualError(t, err, `task "ship" depends on unknown task "payment"`)
+	})
+
+	t.Run("duplicate task", func(t *testing.T) {
+		_, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("ship", nil, nil).
+			AddTask("ship", nil, nil).
+			Plan()
+		assert.EqualError(t, err, `task "ship" added twice`)
+	})
+}
+
+func TestDependencyRunnerRun(t *testing.T) {
+	t.Run("runs after dependencies and in parallel", func(t *testing.T) {
+		start := time.Now()
+		res, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("ship", []string{"payment", "inventory"}, record("ship", 0, nil)).
+			AddTask("payment", nil, record("payment", 100*time.Millisecond, nil)).
+			AddTask("inventory", nil, record("inventory", 100*time.Millisecond, nil)).
+			Run()
+		assert.NoError(t, err)
+		assert.Len(t, res.steps, 3)
+		assert.Equal(t, "ship", res.steps[2])
+		assert.Less(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("skips dependents of a failed task", func(t *testing.T) {
+		res, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("payment", nil, record("payment", 0, errFoo)).
+			AddTask("inventory", nil, record("inventory", 0, nil)).
+			AddTask("ship", []string{"payment", "inventory"}, record("ship", 0, nil)).
+			AddTask("invoice", []string{"ship"}, record("invoice", 0, nil)).
+			Run()
+		assert.ErrorIs(t, err, errFoo)
+		assert.EqualError(t, err, "task payment: error in processFoo")
+		assert.ElementsMatch(t, []string{"payment", "inventory"}, res.steps)
+	})
+
+	t.Run("reports panics", func(t *testing.T) {
+		_, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("boom", nil, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error { panic("boom") }).
+			Run()
+		var panicErr *goctx.PanicError
+		assert.True(t, errors.As(err, &panicErr))
+	})
+
+	t.Run("stops starting tasks once ctx is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		res, err := NewDependencyRunner(ctx, checkout{}).
+			AddTask("first", nil, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error {
+				cancel()
+				return record("first", 0, nil)(ctx, taskReq, mu)
+			}).
+			AddTask("second", []string{"first"}, record("second", 0, nil)).
+			Run()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"first"}, res.steps)
+	})
+	t.Run("siblings share the request through mu", func(t *testing.T) {
+		// Run with -race: the reader and the writer are in the same level and run concurrently
+		var seen int
+		res, err := NewDependencyRunner(context.TODO(), checkout{}).
+			AddTask("price", nil, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error {
+				mu.Lock()
+				defer mu.Unlock()
+				taskReq.total = 42
+				return nil
+			}).
+			AddTask("audit", nil, func(ctx context.Context, taskReq *checkout, mu *sync.RWMutex) error {
+				mu.RLock()
+				defer mu.RUnlock()
+				seen = taskReq.total
+				return nil
+			}).
+			Run()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, res.total)
+		assert.Contains(t, []int{0, 42}, seen)
+	})
+}
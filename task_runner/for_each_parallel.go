@@ -0,0 +1,73 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ItemExecutor processes a single item of a slice.
+type ItemExecutor[Item any] func(ctx context.Context, item Item) error
+
+// ForEachParallel runs fn for every item using at most workers goroutines.
+// Unlike SimpleTaskRunner there is no shared request; each call only sees its own item.
+// All item errors are collected and joined, each prefixed with the item index.
+// Once ctx is done no new items are scheduled and the context error is returned
+// alongside any item errors. A context cancelled after every item was scheduled is not reported.
+func ForEachParallel[Item any](ctx context.Context, items []Item, workers int, fn ItemExecutor[Item]) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	type indexedItem struct {
+		idx  int
+		item Item
+	}
+
+	itemChan := make(chan indexedItem)
+	errChan := make(chan error)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for it := range itemChan {
+				if err := fn(ctx, it.item); err != nil {
+					errChan <- fmt.Errorf("item %d: %w", it.idx, err)
+				}
+			}
+		}()
+	}
+
+	// stopped is only read once errChan is closed, after the feeder has returned
+	stopped := false
+	go func() {
+		defer close(itemChan)
+		for i, item := range items {
+			select {
+			case <-ctx.Done():
+				stopped = true
+				return
+			case itemChan <- indexedItem{idx: i, item: item}:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(errChan)
+	}()
+
+	var err error
+	for goErr := range errChan {
+		err = errors.Join(err, goErr)
+	}
+	if stopped {
+		err = errors.Join(err, ctx.Err())
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachParallel(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	var sum atomic.Int64
+	err := ForEachParallel(context.TODO(), items, 8, func(ctx context.Context, item int) error {
+		sum.Add(int64(item))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5050), sum.Load())
+}
+
+func TestForEachParallelRespectsWorkers(t *testing.T) {
+	var concurrent, maxConcurrent atomic.Int32
+	err := ForEachParallel(context.TODO(), make([]struct{}, 20), 3, func(ctx context.Context, _ struct{}) error {
+		curr := concurrent.Add(1)
+		for {
+			prev := maxConcurrent.Load()
+			if curr <= prev || maxConcurrent.CompareAndSwap(prev, curr) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		concurrent.Add(-1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, maxConcurrent.Load(), int32(3))
+}
+
+func TestForEachParallelCollectsErrors(t *testing.T) {
+	err := ForEachParallel(context.TODO(), []int{1, 2, 3, 4}, 2, func(ctx context.Context, item int) error {
+		if item%2 == 0 {
+			return errFoo
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errFoo)
+	assert.Contains(t, err.Error(), "item 1")
+	assert.Contains(t, err.Error(), "item 3")
+}
+
+func TestForEachParallelCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	var calls atomic.Int32
+	err := ForEachParallel(ctx, make([]int, 50), 1, func(ctx context.Context, item int) error {
+		calls.Add(1)
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, calls.Load(), int32(50))
+}
+
+func TestForEachParallelCancelledAfterLastItem(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	err := ForEachParallel(ctx, []int{1}, 1, func(ctx context.Context, item int) error {
+		cancel()
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestForEachParallelEmpty(t *testing.T) {
+	err := ForEachParallel(context.TODO(), []int{}, 4, func(ctx context.Context, item int) error {
+		return errFoo
+	})
+	assert.NoError(t, err)
+}
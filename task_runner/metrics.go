@@ -0,0 +1,51 @@
+package taskrunner
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder receives Prometheus-style instrumentation for each task
+// executed by a SimpleTaskRunner.
+type MetricsRecorder interface {
+	IncTaskRun(name string)
+	IncTaskError(name string)
+	ObserveDuration(name string, d time.Duration)
+}
+
+// WithMetrics records task runs, failures and durations on the given recorder.
+// Passing nil disables metrics.
+func (s *SimpleTaskRunner[T]) WithMetrics(metrics MetricsRecorder) *SimpleTaskRunner[T] {
+	s.metrics = metrics
+	return s
+}
+
+// instrument runs fn on behalf of task and reports it to the metrics recorder if one is configured.
+// An empty name is derived from the task function.
+func (s *SimpleTaskRunner[T]) instrument(name string, task any, fn func() error) error {
+	if s.metrics == nil {
+		return fn()
+	}
+	if name == "" {
+		name = taskName(task)
+	}
+	start := time.Now()
+	s.metrics.IncTaskRun(name)
+	err := fn()
+	s.metrics.ObserveDuration(name, time.Since(start))
+	if err != nil {
+		s.metrics.IncTaskError(name)
+	}
+	return err
+}
+
+// taskName derives a readable name for a task from its function
+func taskName(fn any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
@@ -0,0 +1,93 @@
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMetrics struct {
+	mu        sync.Mutex
+	runs      map[string]int
+	errors    map[string]int
+	durations map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		runs:      map[string]int{},
+		errors:    map[string]int{},
+		durations: map[string]int{},
+	}
+}
+
+func (f *fakeMetrics) IncTaskRun(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs[name]++
+}
+
+func (f *fakeMetrics) IncTaskError(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors[name]++
+}
+
+func (f *fakeMetrics) ObserveDuration(name string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.durations[name]++
+}
+
+func TestSimpleTaskRunnerWithMetrics(t *testing.T) {
+	metrics := newFakeMetrics()
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+
+	_, err := NewSimpleTaskRunner(context.TODO(), req).
+		WithMetrics(metrics).
+		Then(processFoo).
+		Parallel(processBarParallel).
+		Parallel(processFooParallelError).
+		Result()
+	assert.ErrorIs(t, err, errFoo)
+
+	assert.Equal(t, map[string]int{
+		"task_runner.processFoo":              1,
+		"task_runner.processBarParallel":      1,
+		"task_runner.processFooParallelError": 1,
+	}, metrics.runs)
+	assert.Equal(t, map[string]int{"task_runner.processFooParallelError": 1}, metrics.errors)
+	assert.Equal(t, metrics.runs, metrics.durations)
+}
+
+func TestSimpleTaskRunnerWithoutMetrics(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+
+	res, err := NewSimpleTaskRunner(context.TODO(), req).
+		WithMetrics(nil).
+		Then(processFoo).
+		Result()
+	assert.NoError(t, err)
+	assert.True(t, res.isFoo)
+}
+
+func TestSimpleTaskRunnerMetricsUseTaskName(t *testing.T) {
+	metrics := newFakeMetrics()
+	_, err := NewSimpleTaskRunner(context.TODO(), struct{}{}).
+		WithMetrics(metrics).
+		ThenNamed("load", func(ctx context.Context, taskReq *struct{}) (any, error) {
+			return 1, nil
+		}).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"load": 1}, metrics.runs)
+}
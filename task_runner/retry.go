@@ -0,0 +1,34 @@
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
+)
+
+// WithRetry wraps exec so it is called again when it fails, up to attempts calls in total.
+// backoff returns the wait before each retry, starting at 1 for the first one, and may be nil.
+// Waiting stops with the context error once ctx is done, otherwise the error of the
+// last attempt is returned unchanged.
+func WithRetry[T any](exec TaskExecutor[T], attempts int, backoff func(attempt int) time.Duration) TaskExecutor[T] {
+	policy := retry.RetryPolicy{MaxAttempts: attempts, Backoff: backoff}
+	return func(ctx context.Context, taskReq *T) error {
+		_, err := retry.Retry(ctx, policy, func() (struct{}, error) {
+			return struct{}{}, exec(ctx, taskReq)
+		})
+		return err
+	}
+}
+
+// WithRetryParallel is WithRetry for parallel tasks
+func WithRetryParallel[T any](exec ParallelExecutor[T], attempts int, backoff func(attempt int) time.Duration) ParallelExecutor[T] {
+	policy := retry.RetryPolicy{MaxAttempts: attempts, Backoff: backoff}
+	return func(ctx context.Context, taskReq *T, mu *sync.RWMutex) error {
+		_, err := retry.Retry(ctx, policy, func() (struct{}, error) {
+			return struct{}{}, exec(ctx, taskReq, mu)
+		})
+		return err
+	}
+}
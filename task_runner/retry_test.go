@@ -0,0 +1,75 @@
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mahadev-k/go-utils/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+type retryRequest struct {
+	calls int
+}
+
+func flakyTask(failures int) TaskExecutor[retryRequest] {
+	return func(ctx context.Context, taskReq *retryRequest) error {
+		taskReq.calls++
+		if taskReq.calls <= failures {
+			return errFoo
+		}
+		return nil
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		res, err := NewSimpleTaskRunner(context.TODO(), retryRequest{}).
+			Then(WithRetry(flakyTask(2), 3, retry.Constant(time.Millisecond))).
+			Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, res.calls)
+	})
+
+	t.Run("returns last error unchanged", func(t *testing.T) {
+		res, err := NewSimpleTaskRunner(context.TODO(), retryRequest{}).
+			Then(WithRetry(flakyTask(5), 3, nil)).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.Equal(t, 3, res.calls)
+	})
+
+	t.Run("stops waiting when ctx is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		res, err := NewSimpleTaskRunner(ctx, retryRequest{}).
+			Then(WithRetry(flakyTask(5), 3, retry.Constant(time.Second))).
+			Result()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, res.calls)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestWithRetryParallel(t *testing.T) {
+	calls := 0
+	task := func(ctx context.Context, taskReq *retryRequest, mu *sync.RWMutex) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 2 {
+			return errFoo
+		}
+		taskReq.calls = calls
+		return nil
+	}
+
+	res, err := NewSimpleTaskRunner(context.TODO(), retryRequest{}).
+		Parallel(WithRetryParallel(task, 2, nil)).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.calls)
+}
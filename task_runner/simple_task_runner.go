@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
+
+	"github.com/mahadev-k/go-utils/collections"
+	"github.com/mahadev-k/go-utils/goctx"
 )
 
 /**
@@ -17,6 +21,21 @@ type SimpleTaskRunner[T any] struct {
 	mu sync.RWMutex
 	tasks []TaskExecutor[T]
 	parallelTasks []ParallelExecutor[T]
+	metrics MetricsRecorder
+	failFast bool
+	continueOnError bool
+	timeout time.Duration
+	panicPolicy goctx.PanicPolicy
+	// names holds the names given to serial tasks through ThenNamed, by task index
+	names map[int]string
+	outputs map[string]any
+	// stepErrs and stepDurations record the outcome of named serial tasks during Result
+	stepErrs map[string]error
+	stepDurations map[string]time.Duration
+	catch []func(err error)
+	finally []func()
+	// compensations holds the undo actions registered through ThenWithCompensation, by task index
+	compensations map[int]TaskExecutor[T]
 }
 
 func NewSimpleTaskRunner[T any](ctx context.Context, taskReq T) *SimpleTaskRunner[T] {
@@ -24,6 +43,7 @@ func NewSimpleTaskRunner[T any](ctx context.Context, taskReq T) *SimpleTaskRunne
 		ctx: ctx,
 		taskReq: taskReq,
 		mu: sync.RWMutex{},
+		panicPolicy: goctx.PanicRecoverAsError,
 	}
 }
 
@@ -32,39 +52,239 @@ func (s *SimpleTaskRunner[T]) Then(taskExec TaskExecutor[T]) *SimpleTaskRunner[T
 	return s
 }
 
+// ThenNamed adds a serial task whose output is kept under name.
+// The outputs of all named tasks are returned by ResultWithOutputs,
+// the name is also used when reporting metrics for the task.
+func (s *SimpleTaskRunner[T]) ThenNamed(name string, taskExec TaskExecutorR[T]) *SimpleTaskRunner[T] {
+	if s.names == nil {
+		s.names = make(map[int]string)
+	}
+	s.names[len(s.tasks)] = name
+	return s.Then(func(ctx context.Context, taskReq *T) error {
+		out, err := taskExec(ctx, taskReq)
+		if err != nil {
+			return err
+		}
+		if s.outputs != nil {
+			s.outputs[name] = out
+		}
+		return nil
+	})
+}
+
+// ThenStep adds a serial task under name, so its error and duration are reported by
+// StepResults and StepDurations and metrics use the name
+func (s *SimpleTaskRunner[T]) ThenStep(name string, taskExec TaskExecutor[T]) *SimpleTaskRunner[T] {
+	if s.names == nil {
+		s.names = make(map[int]string)
+	}
+	s.names[len(s.tasks)] = name
+	return s.Then(taskExec)
+}
+
+// StepResults returns the error of every named serial task run by the last Result, nil for
+// the ones that succeeded. Tasks named through ThenStep or ThenNamed are included,
+// tasks that did not run have no entry.
+func (s *SimpleTaskRunner[T]) StepResults() map[string]error {
+	return s.stepErrs
+}
+
+// StepDurations returns how long every named serial task run by the last Result took
+func (s *SimpleTaskRunner[T]) StepDurations() map[string]time.Duration {
+	return s.stepDurations
+}
+
+// ThenWithCompensation adds a serial task along with undo, which reverts it.
+// When a later serial task fails, Result runs the undo actions of the tasks that
+// completed in reverse order and joins their errors with the failure.
+// Undo actions run even if the runner context was cancelled.
+func (s *SimpleTaskRunner[T]) ThenWithCompensation(do TaskExecutor[T], undo TaskExecutor[T]) *SimpleTaskRunner[T] {
+	if s.compensations == nil {
+		s.compensations = make(map[int]TaskExecutor[T])
+	}
+	s.compensations[len(s.tasks)] = undo
+	return s.Then(do)
+}
+
+// Tap registers an observation point that calls fn with the current shared request.
+// It runs in declaration order with the Then tasks and never fails the chain,
+// which makes it handy for logging intermediate state while debugging.
+func (s *SimpleTaskRunner[T]) Tap(fn func(taskReq *T)) *SimpleTaskRunner[T] {
+	return s.Then(func(ctx context.Context, taskReq *T) error {
+		fn(taskReq)
+		return nil
+	})
+}
+
 func (s *SimpleTaskRunner[T]) Parallel(parallelExec ParallelExecutor[T]) *SimpleTaskRunner[T] {
 	s.parallelTasks = append(s.parallelTasks, parallelExec)
 	return s
 }
 
+// FailFast makes the parallel tasks share a cancellable context that is cancelled
+// as soon as any of them fails. Tasks that have not started yet are skipped and
+// running tasks observe the cancellation through the ctx they are passed.
+func (s *SimpleTaskRunner[T]) FailFast() *SimpleTaskRunner[T] {
+	s.failFast = true
+	return s
+}
+
+// ContinueOnError makes the serial tasks keep running after a failure.
+// Every error is joined into the error returned by Result along with the partially
+// updated request. Compensations registered with ThenWithCompensation are not run.
+func (s *SimpleTaskRunner[T]) ContinueOnError() *SimpleTaskRunner[T] {
+	s.continueOnError = true
+	return s
+}
+
+// WithPanicPolicy sets how panics in parallel tasks are handled.
+// The default goctx.PanicRecoverAsError reports the panic as a *goctx.PanicError including
+// the stack trace while the other tasks finish, goctx.PanicPropagate crashes the process.
+func (s *SimpleTaskRunner[T]) WithPanicPolicy(policy goctx.PanicPolicy) *SimpleTaskRunner[T] {
+	s.panicPolicy = policy
+	return s
+}
+
+// WithTimeout caps how long Result may take. Serial and parallel tasks receive a context
+// with the deadline, once it passes no further serial or parallel task starts and Result returns
+// context.DeadlineExceeded joined with the task errors.
+func (s *SimpleTaskRunner[T]) WithTimeout(d time.Duration) *SimpleTaskRunner[T] {
+	s.timeout = d
+	return s
+}
+
+// Catch registers fn to be called with the error of Result when any task failed
+func (s *SimpleTaskRunner[T]) Catch(fn func(err error)) *SimpleTaskRunner[T] {
+	s.catch = append(s.catch, fn)
+	return s
+}
+
+// Finally registers fn to be called once all tasks have run, whatever the outcome.
+// Finally hooks run after the Catch hooks.
+func (s *SimpleTaskRunner[T]) Finally(fn func()) *SimpleTaskRunner[T] {
+	s.finally = append(s.finally, fn)
+	return s
+}
+
 func (s *SimpleTaskRunner[T]) Result() (T, error) {
+	if s.timeout > 0 {
+		parent := s.ctx
+		ctx, cancel := context.WithTimeout(parent, s.timeout)
+		s.ctx = ctx
+		defer func() {
+			cancel()
+			s.ctx = parent
+		}()
+	}
+
 	err := s.serialExecutor()
-	err = errors.Join(err, s.parallelExecutor())
+	if s.timeout == 0 || s.ctx.Err() == nil {
+		// Parallel tasks are not started once the serial ones used up the deadline
+		err = errors.Join(err, s.parallelExecutor())
+	}
+	if s.timeout > 0 {
+		if ctxErr := s.ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+			err = errors.Join(err, ctxErr)
+		}
+	}
+	if err != nil {
+		for _, fn := range s.catch {
+			fn(err)
+		}
+	}
+	for _, fn := range s.finally {
+		fn()
+	}
 	return s.taskReq, err
 }
 
+// ResultWithOutputs runs the tasks like Result and also returns the outputs of
+// the ThenNamed tasks keyed by their name. Tasks that did not run or failed have no entry.
+func (s *SimpleTaskRunner[T]) ResultWithOutputs() (T, map[string]any, error) {
+	s.outputs = make(map[string]any)
+	defer func() { s.outputs = nil }()
+
+	res, err := s.Result()
+	return res, s.outputs, err
+}
 
 func (s* SimpleTaskRunner[T]) serialExecutor() error {
-	for _, task := range(s.tasks) {
-		err := task(s.ctx, &s.taskReq)
+	s.stepErrs = make(map[string]error)
+	s.stepDurations = make(map[string]time.Duration)
+	var errs []error
+	// completed holds the undo actions of the tasks that succeeded, the latest on top
+	var completed collections.Stack[TaskExecutor[T]]
+	for i, task := range(s.tasks) {
+		if ctxErr := s.ctx.Err(); s.timeout > 0 && ctxErr != nil {
+			// Do not start further tasks once the deadline passed
+			if s.continueOnError {
+				return errors.Join(append(errs, ctxErr)...)
+			}
+			return errors.Join(ctxErr, s.compensate(&completed))
+		}
+		start := time.Now()
+		err := s.instrument(s.names[i], task, func() error {
+			return task(s.ctx, &s.taskReq)
+		})
+		if name, ok := s.names[i]; ok {
+			s.stepErrs[name] = err
+			s.stepDurations[name] = time.Since(start)
+		}
+		if err != nil && s.continueOnError {
+			errs = append(errs, err)
+			continue
+		}
 		if err != nil {
-			return err
+			return errors.Join(err, s.compensate(&completed))
+		}
+		if undo, ok := s.compensations[i]; ok {
+			completed.Push(undo)
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// compensate pops and runs the undo actions of the completed serial tasks, latest first
+func (s *SimpleTaskRunner[T]) compensate(completed *collections.Stack[TaskExecutor[T]]) error {
+	ctx := context.WithoutCancel(s.ctx)
+	var errs []error
+	for undo, ok := completed.Pop(); ok; undo, ok = completed.Pop() {
+		if err := undo(ctx, &s.taskReq); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (s *SimpleTaskRunner[T]) parallelExecutor() error {
 	errChan := make(chan error)
 	wg := sync.WaitGroup{}
 	var err error
+
+	ctx, cancel := s.ctx, context.CancelFunc(func() {})
+	if s.failFast {
+		ctx, cancel = context.WithCancel(s.ctx)
+	}
+	defer cancel()
+
 	for _, task := range(s.parallelTasks) {
 		wg.Add(1)
 		go func(ctx context.Context, mu *sync.RWMutex, taskReq *T) {
 			defer wg.Done()
-			err := task(ctx, taskReq, mu)
+			if s.failFast && ctx.Err() != nil {
+				// Skip tasks scheduled after a failure
+				return
+			}
+			err := s.instrument("", task, func() error {
+				return s.panicPolicy.Call(func() error {
+					return task(ctx, taskReq, mu)
+				})
+			})
+			if err != nil && s.failFast {
+				cancel()
+			}
 			errChan <- err
-		}(s.ctx, &s.mu, &s.taskReq)
+		}(ctx, &s.mu, &s.taskReq)
 	}
 	
 	go func ()  {
@@ -2,10 +2,15 @@ package taskrunner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/mahadev-k/go-utils/goctx"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -116,4 +121,338 @@ func TestSimpleTaskRunnerParallelError(t *testing.T) {
 	
 	assert.Error(t, errFoo, err)
 	assert.Equal(t, true, res.isBar)
-}
\ No newline at end of file
+}
+func processWaitParallel(ctx context.Context, taskReq *struct{isFoo bool; isBar bool}, mu *sync.RWMutex) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		mu.Lock()
+		defer mu.Unlock()
+		taskReq.isBar = true
+		return nil
+	}
+}
+
+func TestSimpleTaskRunnerParallelFailFast(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+	ctx := context.TODO()
+	start := time.Now()
+	res, err := NewSimpleTaskRunner(ctx, req).
+		FailFast().
+		Parallel(processWaitParallel).
+		Parallel(processFooParallelError).
+		Result()
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, err, errFoo)
+	assert.False(t, res.isBar)
+}
+
+func TestSimpleTaskRunnerParallelWithoutFailFast(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewSimpleTaskRunner(ctx, req).
+		Parallel(processWaitParallel).
+		Parallel(processFooParallelError).
+		Result()
+
+	// Without fail fast the waiting task only stops on the parent deadline
+	assert.ErrorIs(t, err, errFoo)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSimpleTaskRunnerTap(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+	var observed []struct{ isFoo, isBar bool }
+	tap := func(taskReq *struct{isFoo bool; isBar bool}) {
+		observed = append(observed, struct{ isFoo, isBar bool }{taskReq.isFoo, taskReq.isBar})
+	}
+
+	res, err := NewSimpleTaskRunner(context.TODO(), req).
+		Tap(tap).
+		Then(processFoo).
+		Tap(tap).
+		Then(processBar).
+		Tap(tap).
+		Result()
+
+	assert.NoError(t, err)
+	assert.True(t, res.isFoo)
+	assert.True(t, res.isBar)
+	assert.Equal(t, []struct{ isFoo, isBar bool }{
+		{false, false},
+		{true, false},
+		{true, true},
+	}, observed)
+}
+
+func processPanicParallel(ctx context.Context, taskReq *struct{isFoo bool; isBar bool}, mu *sync.RWMutex) error {
+	panic("parallel task panicked")
+}
+
+func TestSimpleTaskRunnerParallelRecoverPanic(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+	res, err := NewSimpleTaskRunner(context.TODO(), req).
+		WithPanicPolicy(goctx.PanicRecoverAsError).
+		Parallel(processPanicParallel).
+		Parallel(processBarParallel).
+		Result()
+
+	var panicErr *goctx.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "parallel task panicked", panicErr.Value)
+	assert.True(t, res.isBar)
+}
+
+func TestSimpleTaskRunnerResultWithOutputs(t *testing.T) {
+	type request struct{ userID int }
+
+	t.Run("collects named outputs", func(t *testing.T) {
+		res, outputs, err := NewSimpleTaskRunner(context.TODO(), request{userID: 7}).
+			ThenNamed("user", func(ctx context.Context, taskReq *request) (any, error) {
+				return fmt.Sprintf("user-%d", taskReq.userID), nil
+			}).
+			Then(func(ctx context.Context, taskReq *request) error {
+				taskReq.userID++
+				return nil
+			}).
+			ThenNamed("orders", func(ctx context.Context, taskReq *request) (any, error) {
+				return []int{taskReq.userID}, nil
+			}).
+			ResultWithOutputs()
+		assert.NoError(t, err)
+		assert.Equal(t, 8, res.userID)
+		assert.Equal(t, map[string]any{"user": "user-7", "orders": []int{8}}, outputs)
+	})
+
+	t.Run("failed task has no output", func(t *testing.T) {
+		_, outputs, err := NewSimpleTaskRunner(context.TODO(), request{}).
+			ThenNamed("user", func(ctx context.Context, taskReq *request) (any, error) {
+				return "user", nil
+			}).
+			ThenNamed("orders", func(ctx context.Context, taskReq *request) (any, error) {
+				return nil, errFoo
+			}).
+			ResultWithOutputs()
+		assert.ErrorIs(t, err, errFoo)
+		assert.Equal(t, map[string]any{"user": "user"}, outputs)
+	})
+}
+
+func TestSimpleTaskRunnerContinueOnError(t *testing.T) {
+	errBar := errors.New("error in processBar")
+	type cleanup struct{ removed []string }
+	remove := func(name string, err error) TaskExecutor[cleanup] {
+		return func(ctx context.Context, taskReq *cleanup) error {
+			if err != nil {
+				return err
+			}
+			taskReq.removed = append(taskReq.removed, name)
+			return nil
+		}
+	}
+
+	res, err := NewSimpleTaskRunner(context.TODO(), cleanup{}).
+		ContinueOnError().
+		Then(remove("tmp", nil)).
+		Then(remove("cache", errFoo)).
+		Then(remove("logs", nil)).
+		Then(remove("locks", errBar)).
+		Result()
+	assert.ErrorIs(t, err, errFoo)
+	assert.ErrorIs(t, err, errBar)
+	assert.Equal(t, []string{"tmp", "logs"}, res.removed)
+
+	// Fail fast stays the default
+	res, err = NewSimpleTaskRunner(context.TODO(), cleanup{}).
+		Then(remove("tmp", nil)).
+		Then(remove("cache", errFoo)).
+		Then(remove("logs", nil)).
+		Result()
+	assert.ErrorIs(t, err, errFoo)
+	assert.Equal(t, []string{"tmp"}, res.removed)
+}
+
+func TestSimpleTaskRunnerParallelRecoverPanicByDefault(t *testing.T) {
+	req := struct {
+		isFoo bool
+		isBar bool
+	}{}
+	res, err := NewSimpleTaskRunner(context.TODO(), req).
+		Parallel(processPanicParallel).
+		Parallel(processBarParallel).
+		Parallel(processFooParallel).
+		Result()
+
+	var panicErr *goctx.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "parallel task panicked", panicErr.Value)
+	assert.True(t, res.isBar)
+	assert.True(t, res.isFoo)
+}
+
+func TestSimpleTaskRunnerStepResults(t *testing.T) {
+	type request struct{ steps int }
+	sleepy := func(d time.Duration, err error) TaskExecutor[request] {
+		return func(ctx context.Context, taskReq *request) error {
+			time.Sleep(d)
+			taskReq.steps++
+			return err
+		}
+	}
+
+	runner := NewSimpleTaskRunner(context.TODO(), request{})
+	_, err := runner.
+		ThenStep("fetch", sleepy(10*time.Millisecond, nil)).
+		Then(sleepy(0, nil)).
+		ThenNamed("parse", func(ctx context.Context, taskReq *request) (any, error) {
+			return nil, errFoo
+		}).
+		ThenStep("store", sleepy(0, nil)).
+		Result()
+	assert.ErrorIs(t, err, errFoo)
+
+	assert.Equal(t, map[string]error{"fetch": nil, "parse": errFoo}, runner.StepResults())
+	durations := runner.StepDurations()
+	assert.Len(t, durations, 2)
+	assert.GreaterOrEqual(t, durations["fetch"], 10*time.Millisecond)
+}
+
+func TestSimpleTaskRunnerCatchFinally(t *testing.T) {
+	type request struct{ done bool }
+	var events []string
+	catch := func(err error) { events = append(events, "catch: "+err.Error()) }
+	finally := func() { events = append(events, "finally") }
+
+	_, err := NewSimpleTaskRunner(context.TODO(), request{}).
+		Catch(catch).
+		Finally(finally).
+		Then(func(ctx context.Context, taskReq *request) error { return errFoo }).
+		Parallel(func(ctx context.Context, taskReq *request, mu *sync.RWMutex) error {
+			events = append(events, "parallel")
+			return nil
+		}).
+		Result()
+	assert.ErrorIs(t, err, errFoo)
+	assert.Equal(t, []string{"parallel", "catch: error in processFoo", "finally"}, events)
+
+	events = nil
+	res, err := NewSimpleTaskRunner(context.TODO(), request{}).
+		Catch(catch).
+		Finally(finally).
+		Then(func(ctx context.Context, taskReq *request) error {
+			taskReq.done = true
+			return nil
+		}).
+		Result()
+	assert.NoError(t, err)
+	assert.True(t, res.done)
+	assert.Equal(t, []string{"finally"}, events)
+}
+
+func TestSimpleTaskRunnerWithTimeout(t *testing.T) {
+	type request struct{ steps int }
+	slow := func(ctx context.Context, taskReq *request) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(30 * time.Millisecond):
+			taskReq.steps++
+			return nil
+		}
+	}
+
+	t.Run("cuts off a slow serial chain", func(t *testing.T) {
+		start := time.Now()
+		res, err := NewSimpleTaskRunner(context.TODO(), request{}).
+			WithTimeout(50 * time.Millisecond).
+			Then(slow).
+			Then(slow).
+			Then(slow).
+			Then(slow).
+			Result()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, res.steps)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("skips parallel tasks once the deadline passed", func(t *testing.T) {
+		var parallelRuns atomic.Int32
+		parallel := func(ctx context.Context, taskReq *request, mu *sync.RWMutex) error {
+			parallelRuns.Add(1)
+			return ctx.Err()
+		}
+		_, err := NewSimpleTaskRunner(context.TODO(), request{}).
+			WithTimeout(20 * time.Millisecond).
+			Then(slow).
+			Parallel(parallel).
+			Parallel(parallel).
+			Result()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, strings.Count(err.Error(), context.DeadlineExceeded.Error()))
+		assert.Zero(t, parallelRuns.Load())
+	})
+
+	t.Run("parallel tasks get the deadline", func(t *testing.T) {
+		var deadline time.Time
+		_, err := NewSimpleTaskRunner(context.TODO(), request{}).
+			WithTimeout(time.Second).
+			Parallel(func(ctx context.Context, taskReq *request, mu *sync.RWMutex) error {
+				deadline, _ = ctx.Deadline()
+				return errFoo
+			}).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	})
+
+	t.Run("joins task errors", func(t *testing.T) {
+		_, err := NewSimpleTaskRunner(context.TODO(), request{}).
+			WithTimeout(10 * time.Millisecond).
+			Parallel(func(ctx context.Context, taskReq *request, mu *sync.RWMutex) error {
+				return errFoo
+			}).
+			Parallel(func(ctx context.Context, taskReq *request, mu *sync.RWMutex) error {
+				<-ctx.Done()
+				return nil
+			}).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSimpleTaskRunnerCancelledParentWithoutTimeout(t *testing.T) {
+	type request struct{ steps int }
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	// Without WithTimeout the serial tasks decide themselves whether to honor ctx
+	res, err := NewSimpleTaskRunner(ctx, request{}).
+		Then(func(ctx context.Context, taskReq *request) error {
+			taskReq.steps++
+			return nil
+		}).
+		Then(func(ctx context.Context, taskReq *request) error {
+			taskReq.steps++
+			return nil
+		}).
+		Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.steps)
+}
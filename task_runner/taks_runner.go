@@ -10,6 +10,8 @@ import (
 * bothering about every piece of error handling.
 **/
 type TaskExecutor[T any] func(ctx context.Context, taskReq *T) error
+// TaskExecutorR is a task that also produces an output, see SimpleTaskRunner.ThenNamed
+type TaskExecutorR[T any] func(ctx context.Context, taskReq *T) (any, error)
 type ParallelExecutor[T any] func(ctx context.Context, taskReq *T, mu *sync.RWMutex) error
 type TaskRunner[T any] interface {
 	Then(taskExec TaskExecutor[T]) TaskRunner[T]
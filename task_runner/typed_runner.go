@@ -0,0 +1,72 @@
+package taskrunner
+
+import "context"
+
+// TypedExecutor is a task that returns a result instead of storing it in the request
+type TypedExecutor[T any, R any] func(ctx context.Context, taskReq *T) (R, error)
+
+// TypedRunner runs tasks in order like SimpleTaskRunner.Then and collects their results,
+// much like StatefulExec in dbutils collects into processedRes
+type TypedRunner[T any, R any] struct {
+	ctx     context.Context
+	taskReq T
+	tasks   []TypedExecutor[T, R]
+	// names holds the name of every task, empty for tasks added with Then
+	names []string
+}
+
+func NewTypedRunner[T any, R any](ctx context.Context, taskReq T) *TypedRunner[T, R] {
+	return &TypedRunner[T, R]{
+		ctx:     ctx,
+		taskReq: taskReq,
+	}
+}
+
+func (r *TypedRunner[T, R]) Then(taskExec TypedExecutor[T, R]) *TypedRunner[T, R] {
+	return r.ThenNamed("", taskExec)
+}
+
+// ThenNamed adds a task whose result is returned by ResultNamed under name
+func (r *TypedRunner[T, R]) ThenNamed(name string, taskExec TypedExecutor[T, R]) *TypedRunner[T, R] {
+	r.tasks = append(r.tasks, taskExec)
+	r.names = append(r.names, name)
+	return r
+}
+
+// Result runs the tasks in order and returns their results in the same order.
+// It stops at the first error, returning the results of the tasks that succeeded before it.
+func (r *TypedRunner[T, R]) Result() ([]R, error) {
+	results := make([]R, 0, len(r.tasks))
+	err := r.run(func(i int, res R) {
+		results = append(results, res)
+	})
+	return results, err
+}
+
+// ResultNamed runs the tasks like Result and returns the results of the named tasks keyed by name
+func (r *TypedRunner[T, R]) ResultNamed() (map[string]R, error) {
+	results := make(map[string]R)
+	err := r.run(func(i int, res R) {
+		if r.names[i] != "" {
+			results[r.names[i]] = res
+		}
+	})
+	return results, err
+}
+
+// Request returns the shared request as left by the tasks
+func (r *TypedRunner[T, R]) Request() T {
+	return r.taskReq
+}
+
+// run calls every task in order, handing each result to collect
+func (r *TypedRunner[T, R]) run(collect func(i int, res R)) error {
+	for i, task := range r.tasks {
+		res, err := task(r.ctx, &r.taskReq)
+		if err != nil {
+			return err
+		}
+		collect(i, res)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package taskrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type quote struct {
+	items int
+}
+
+func TestTypedRunner(t *testing.T) {
+	subtotal := func(ctx context.Context, taskReq *quote) (float64, error) {
+		taskReq.items = 3
+		return 30, nil
+	}
+	tax := func(ctx context.Context, taskReq *quote) (float64, error) {
+		return float64(taskReq.items) * 0.5, nil
+	}
+	failing := func(ctx context.Context, taskReq *quote) (float64, error) {
+		return 0, errFoo
+	}
+
+	t.Run("collects results in order", func(t *testing.T) {
+		runner := NewTypedRunner[quote, float64](context.TODO(), quote{})
+		res, err := runner.Then(subtotal).Then(tax).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{30, 1.5}, res)
+		assert.Equal(t, 3, runner.Request().items)
+	})
+
+	t.Run("named results", func(t *testing.T) {
+		res, err := NewTypedRunner[quote, float64](context.TODO(), quote{}).
+			ThenNamed("subtotal", subtotal).
+			Then(tax).
+			ThenNamed("tax", tax).
+			ResultNamed()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]float64{"subtotal": 30, "tax": 1.5}, res)
+	})
+
+	t.Run("stops at first error", func(t *testing.T) {
+		res, err := NewTypedRunner[quote, float64](context.TODO(), quote{}).
+			Then(subtotal).
+			Then(failing).
+			Then(tax).
+			Result()
+		assert.ErrorIs(t, err, errFoo)
+		assert.Equal(t, []float64{30}, res)
+	})
+}
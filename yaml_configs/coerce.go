@@ -0,0 +1,65 @@
+package yaml_configs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/mahadev-k/go-utils/internal/numconv"
+)
+
+// coerce converts a stored config value to T.
+// Values that already are a T are returned as is, numbers are converted to other
+// numeric types when no precision is lost, and strings are parsed with strconv
+// when T is a bool or a numeric type, which covers quoted numbers in yaml files.
+func coerce[T any](value any) (T, error) {
+	if typed, ok := value.(T); ok {
+		return typed, nil
+	}
+
+	var result T
+	target := reflect.ValueOf(&result).Elem()
+	if val := reflect.ValueOf(value); numconv.IsNumber(val.Kind()) && numconv.IsNumber(target.Kind()) {
+		converted, err := numconv.Convert(val, target.Type())
+		if err != nil {
+			return result, fmt.Errorf("cannot coerce %v to %s without losing precision", value, target.Type())
+		}
+		target.Set(converted)
+		return result, nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return result, fmt.Errorf("cannot use %T as %s", value, target.Type())
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return result, fmt.Errorf("cannot coerce %q to %s: %w", str, target.Type(), err)
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, target.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("cannot coerce %q to %s: %w", str, target.Type(), err)
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(str, 10, target.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("cannot coerce %q to %s: %w", str, target.Type(), err)
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, target.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("cannot coerce %q to %s: %w", str, target.Type(), err)
+		}
+		target.SetFloat(f)
+	default:
+		return result, fmt.Errorf("cannot use %T as %s", value, target.Type())
+	}
+	return result, nil
+}
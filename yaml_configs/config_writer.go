@@ -0,0 +1,134 @@
+package yaml_configs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set updates the value for a dotted key, creating intermediate maps as needed.
+// The change is remembered so that Save can write it back to a file.
+func (c *Config) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	setNested(c.configMap, key, value)
+	if c.changes == nil {
+		c.changes = make(map[string]any)
+	}
+	c.changes[key] = value
+
+	c.rebuildFlatMap()
+}
+
+// setNested stores value at the dotted key of configMap, creating intermediate maps as needed
+func setNested(configMap map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	current := configMap
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// Save writes the values changed through Set into the yaml file at path.
+// The file is round-tripped through yaml.Node so comments and the order of
+// untouched keys are preserved. A missing file is created.
+func (c *Config) Save(path string) error {
+	root := &yaml.Node{Kind: yaml.DocumentNode}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, root); err != nil {
+			return err
+		}
+	}
+	if len(root.Content) == 0 {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s does not contain a mapping", path)
+	}
+
+	// Apply changes in a stable order so new keys are appended deterministically
+	c.mu.RLock()
+	changes := make(map[string]any, len(c.changes))
+	for key, value := range c.changes {
+		changes[key] = value
+	}
+	c.mu.RUnlock()
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := setNode(root.Content[0], strings.Split(key, "."), changes[key]); err != nil {
+			return fmt.Errorf("setting %s: %w", key, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// setNode sets the value at the dotted path inside a mapping node, keeping the
+// comments attached to the existing key and value nodes
+func setNode(mapping *yaml.Node, path []string, value any) error {
+	if mapping.Kind != yaml.MappingNode {
+		return errors.New("parent is not a mapping")
+	}
+
+	var valueNode *yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == path[0] {
+			valueNode = mapping.Content[i+1]
+			break
+		}
+	}
+
+	if valueNode == nil {
+		valueNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]},
+			valueNode,
+		)
+	}
+
+	if len(path) > 1 {
+		if valueNode.Kind != yaml.MappingNode {
+			*valueNode = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: valueNode.HeadComment, LineComment: valueNode.LineComment, FootComment: valueNode.FootComment}
+		}
+		return setNode(valueNode, path[1:], value)
+	}
+
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return err
+	}
+	encoded.HeadComment = valueNode.HeadComment
+	encoded.LineComment = valueNode.LineComment
+	encoded.FootComment = valueNode.FootComment
+	*valueNode = encoded
+	return nil
+}
@@ -0,0 +1,62 @@
+package yaml_configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavePreservesComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env.yaml")
+	original := `# database settings
+database:
+  # primary host
+  host: localhost
+  port: 5432 # default port
+  user: postgres
+# feature flags
+features:
+  beta: false
+`
+	assert.NoError(t, os.WriteFile(path, []byte(original), 0o644))
+
+	cfg := newTestConfig(t, path)
+	cfg.Set("database.port", 6000)
+	cfg.Set("features.search.enabled", true)
+	assert.Equal(t, 6000, cfg.Get("database.port"))
+	assert.Equal(t, true, cfg.Get("features.search.enabled"))
+
+	assert.NoError(t, cfg.Save(path))
+
+	saved, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `# database settings
+database:
+  # primary host
+  host: localhost
+  port: 6000 # default port
+  user: postgres
+# feature flags
+features:
+  beta: false
+  search:
+    enabled: true
+`, string(saved))
+
+	reloaded := newTestConfig(t, path)
+	assert.Equal(t, 6000, reloaded.Get("database.port"))
+	assert.Equal(t, "localhost", reloaded.Get("database.host"))
+}
+
+func TestSaveCreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.yaml")
+	cfg := newEmptyConfig()
+	cfg.Set("service.name", "orders")
+
+	assert.NoError(t, cfg.Save(path))
+	saved, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "service:\n  name: orders\n", string(saved))
+}
@@ -0,0 +1,99 @@
+package yaml_configs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Get returns the value stored at key in the default config as a T, or the zero value when
+// the key is missing or no config is loaded.
+// String values are coerced to numbers and bools when T asks for one.
+// It panics when the value cannot be converted to T.
+func Get[T any](key string) T {
+	cfg := Default()
+	if cfg == nil {
+		return *new(T)
+	}
+	result, found, err := getAs[T](cfg, key)
+	if found && err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// GetOr returns the value stored at key in the default config as a T like Get, or def when
+// the key is absent. A key present with a zero value returns that zero value, not def.
+func GetOr[T any](key string, def T) T {
+	cfg := Default()
+	if cfg == nil {
+		return def
+	}
+	result, found, err := getAs[T](cfg, key)
+	if !found {
+		return def
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// GetE returns the value stored at key in the default config as a T like Get,
+// but reports a missing key, a missing config or a value that cannot be converted as an error
+func GetE[T any](key string) (T, error) {
+	cfg := Default()
+	if cfg == nil {
+		return *new(T), errors.New("no config loaded")
+	}
+	return getE[T](cfg, key)
+}
+
+// GetWithDefault returns the value stored at key, or def when the key is absent
+func (c *Config) GetWithDefault(key string, def any) any {
+	if value, ok := c.lookup(key); ok {
+		return value
+	}
+	return def
+}
+
+// GetString returns the string stored at key
+func (c *Config) GetString(key string) (string, error) {
+	return getE[string](c, key)
+}
+
+// GetInt returns the value stored at key as an int, converting other numbers and numeric strings
+func (c *Config) GetInt(key string) (int, error) {
+	return getE[int](c, key)
+}
+
+// GetBool returns the value stored at key as a bool, parsing strings such as "true"
+func (c *Config) GetBool(key string) (bool, error) {
+	return getE[bool](c, key)
+}
+
+// GetFloat64 returns the value stored at key as a float64, converting integers and numeric strings
+func (c *Config) GetFloat64(key string) (float64, error) {
+	return getE[float64](c, key)
+}
+
+// getE returns the value stored at key in cfg as a T, a missing key is an error
+func getE[T any](cfg *Config, key string) (T, error) {
+	result, found, err := getAs[T](cfg, key)
+	if !found {
+		return result, fmt.Errorf("config key %s: not found", key)
+	}
+	return result, err
+}
+
+// getAs coerces the value stored at key in cfg to T, found is false for a missing key
+func getAs[T any](cfg *Config, key string) (result T, found bool, err error) {
+	value, ok := cfg.lookup(key)
+	if !ok {
+		return result, false, nil
+	}
+	result, err = coerce[T](value)
+	if err != nil {
+		return result, true, fmt.Errorf("config key %s: %w", key, err)
+	}
+	return result, true, nil
+}
@@ -0,0 +1,113 @@
+package yaml_configs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetE(t *testing.T) {
+	_, err := LoadConfigWithSuffix("./test_data/env", "local")
+	assert.NoError(t, err)
+
+	port, err := GetE[int64]("database.port")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5430), port)
+
+	_, err = GetE[int]("database.missing")
+	assert.EqualError(t, err, "config key database.missing: not found")
+
+	_, err = GetE[int]("database.host")
+	assert.ErrorContains(t, err, `config key database.host: cannot coerce "localhost" to int`)
+
+	SetDefault(nil)
+	_, err = GetE[int]("database.port")
+	assert.EqualError(t, err, "no config loaded")
+}
+
+func TestTypedGetters(t *testing.T) {
+	cfg := newTestConfig(t, "./test_data/env.local.yaml")
+
+	host, err := cfg.GetString("database.host")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", host)
+
+	poolSize, err := cfg.GetInt("database.pool_size")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, poolSize)
+
+	ssl, err := cfg.GetBool("database.ssl")
+	assert.NoError(t, err)
+	assert.True(t, ssl)
+
+	port, err := cfg.GetFloat64("database.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 5430.0, port)
+
+	_, err = cfg.GetString("database.port")
+	assert.ErrorContains(t, err, "cannot use int as string")
+
+	_, err = cfg.GetInt("database.timeout_seconds")
+	assert.Error(t, err)
+
+	_, err = cfg.GetBool("database.missing")
+	assert.EqualError(t, err, "config key database.missing: not found")
+}
+
+func TestCoerceNumbers(t *testing.T) {
+	i64, err := coerce[int64](5430)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5430), i64)
+
+	i, err := coerce[int](2.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, i)
+
+	_, err = coerce[int](2.5)
+	assert.Error(t, err)
+
+	_, err = coerce[int8](300)
+	assert.Error(t, err)
+
+	_, err = coerce[uint](-1)
+	assert.Error(t, err)
+
+	// Rounds up to 2^63, which does not fit an int64 whatever the platform does on the way back
+	_, err = coerce[float64](int64(math.MaxInt64))
+	assert.Error(t, err)
+
+	_, err = coerce[int64](math.Ldexp(1, 63))
+	assert.Error(t, err)
+
+	_, err = coerce[int64](uint64(math.MaxUint64))
+	assert.Error(t, err)
+
+	_, err = coerce[int](math.NaN())
+	assert.Error(t, err)
+
+	f, err := coerce[float64](int64(math.MinInt64))
+	assert.NoError(t, err)
+	assert.Equal(t, -math.Ldexp(1, 63), f)
+}
+
+func TestGetWithDefault(t *testing.T) {
+	cfg := newEmptyConfig()
+	cfg.Set("server.port", 0)
+	cfg.Set("server.debug", false)
+	SetDefault(cfg)
+
+	// Present but zero values win over the default
+	assert.Equal(t, 0, cfg.GetWithDefault("server.port", 8080))
+	assert.Equal(t, 0, GetOr("server.port", 8080))
+	assert.Equal(t, false, GetOr("server.debug", true))
+
+	// Absent keys fall back to the default
+	assert.Equal(t, 30, cfg.GetWithDefault("server.timeout", 30))
+	assert.Equal(t, 30, GetOr("server.timeout", 30))
+
+	assert.Panics(t, func() { GetOr("server.port", "none") })
+
+	SetDefault(nil)
+	assert.Equal(t, 8080, GetOr("server.port", 8080))
+}
@@ -0,0 +1,40 @@
+package yaml_configs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// LoadConfigFromReader loads a yaml config from r, such as an embedded file or a test fixture.
+// The result becomes the default read by Get.
+func LoadConfigFromReader(r io.Reader) (*Config, error) {
+	return LoadConfigFromReaders(r)
+}
+
+// LoadConfigFromBytes loads a yaml config held in memory like LoadConfigFromReader
+func LoadConfigFromBytes(b []byte) (*Config, error) {
+	return LoadConfigFromReaders(bytes.NewReader(b))
+}
+
+// LoadConfigFromReaders loads yaml configs from readers in order, with later readers overriding earlier ones.
+// The result becomes the default read by Get.
+func LoadConfigFromReaders(readers ...io.Reader) (*Config, error) {
+	cfg, err := NewLoader().LoadReaders(readers...)
+	if err != nil {
+		return nil, err
+	}
+	SetDefault(cfg)
+	return cfg, nil
+}
+
+// LoadReaders loads yaml configs from readers in order like LoadConfigFromReaders
+func (l *Loader) LoadReaders(readers ...io.Reader) (*Config, error) {
+	cfg := newConfig(l.options)
+	for i, r := range readers {
+		if err := mergeReader(r, fmt.Sprintf("reader %d", i+1), cfg); err != nil {
+			return nil, fmt.Errorf("reader %d: %w", i+1, err)
+		}
+	}
+	return cfg, nil
+}
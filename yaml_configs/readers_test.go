@@ -0,0 +1,37 @@
+package yaml_configs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigFromReaders(t *testing.T) {
+	cfg, err := LoadConfigFromReaders(
+		strings.NewReader("database:\n  host: localhost\n  port: 5432\n"),
+		strings.NewReader("database:\n  port: 6432\n"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.Get("database.host"))
+	assert.Equal(t, 6432, cfg.Get("database.port"))
+	assert.Same(t, cfg, Default())
+
+	_, err = LoadConfigFromReaders(strings.NewReader("a: 1"), strings.NewReader("a: [1"))
+	assert.ErrorContains(t, err, "reader 2")
+}
+
+func TestLoadConfigFromReaderAndBytes(t *testing.T) {
+	cfg, err := LoadConfigFromReader(strings.NewReader("app:\n  name: gateway\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "gateway", Get[string]("app.name"))
+	assert.Equal(t, "gateway", cfg.Get("APP.NAME"))
+
+	cfg, err = LoadConfigFromBytes([]byte("app:\n  name: worker\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "worker", cfg.Get("app.name"))
+
+	cfg, err = LoadConfigFromBytes(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.Get("app.name"))
+}
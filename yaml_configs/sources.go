@@ -0,0 +1,83 @@
+package yaml_configs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Source provides raw config data from an arbitrary backend such as a config server.
+// format names the encoding of the data, "yaml", "yml", "json" and "" (yaml) are supported.
+type Source interface {
+	Read(ctx context.Context) (data []byte, format string, err error)
+}
+
+// SourceFunc adapts a plain function to the Source interface
+type SourceFunc func(ctx context.Context) ([]byte, string, error)
+
+func (f SourceFunc) Read(ctx context.Context) ([]byte, string, error) {
+	return f(ctx)
+}
+
+// LoadConfigFromSources loads configs from sources in order, with later sources overriding earlier ones.
+// Every Read receives ctx and loading stops with the context error as soon as ctx is done,
+// even if a source does not return in time. The result becomes the default read by Get.
+func LoadConfigFromSources(ctx context.Context, sources ...Source) (*Config, error) {
+	return LoadConfigFromSourcesWithOptions(ctx, sources)
+}
+
+// LoadConfigFromSourcesWithOptions loads configs like LoadConfigFromSources, applying the given options
+func LoadConfigFromSourcesWithOptions(ctx context.Context, sources []Source, opts ...LoadOption) (*Config, error) {
+	cfg, err := NewLoader(opts...).LoadSources(ctx, sources...)
+	if err != nil {
+		return nil, err
+	}
+	SetDefault(cfg)
+	return cfg, nil
+}
+
+// mergeSources reads every source in order and merges it into cfg
+func mergeSources(ctx context.Context, sources []Source, cfg *Config) error {
+	for i, source := range sources {
+		data, format, err := readSource(ctx, source)
+		if err != nil {
+			return fmt.Errorf("source %d: %w", i+1, err)
+		}
+		switch strings.ToLower(format) {
+		case "", "yaml", "yml", "json":
+			// json is a subset of yaml so both go through the yaml decoder
+		default:
+			return fmt.Errorf("source %d: unsupported format %q", i+1, format)
+		}
+		if err := mergeReader(bytes.NewReader(data), fmt.Sprintf("source %d", i+1), cfg); err != nil {
+			return fmt.Errorf("source %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// readSource reads from source, giving up once ctx is done
+func readSource(ctx context.Context, source Source) ([]byte, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	type readResult struct {
+		data   []byte
+		format string
+		err    error
+	}
+	resChan := make(chan readResult, 1)
+	go func() {
+		data, format, err := source.Read(ctx)
+		resChan <- readResult{data: data, format: format, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	case res := <-resChan:
+		return res.data, res.format, res.err
+	}
+}
@@ -0,0 +1,53 @@
+package yaml_configs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staticSource(data string, format string) Source {
+	return SourceFunc(func(ctx context.Context) ([]byte, string, error) {
+		return []byte(data), format, nil
+	})
+}
+
+func newEmptyConfig() *Config {
+	return newConfig(loadOptions{})
+}
+
+func TestMergeSources(t *testing.T) {
+	cfg := newEmptyConfig()
+	err := mergeSources(context.Background(), []Source{
+		staticSource("database:\n  host: localhost\n  port: 5432\n", "yaml"),
+		staticSource(`{"database": {"port": 6432, "user": "remote"}}`, "json"),
+	}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.Get("database.host"))
+	assert.Equal(t, 6432, cfg.Get("database.port"))
+	assert.Equal(t, "remote", cfg.Get("database.user"))
+}
+
+func TestMergeSourcesUnsupportedFormat(t *testing.T) {
+	err := mergeSources(context.Background(), []Source{staticSource("a = 1", "toml")}, newEmptyConfig())
+	assert.ErrorContains(t, err, `source 1: unsupported format "toml"`)
+}
+
+func TestMergeSourcesTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	slow := SourceFunc(func(ctx context.Context) ([]byte, string, error) {
+		// Ignores ctx on purpose, loading must still give up on time
+		time.Sleep(time.Second)
+		return nil, "yaml", nil
+	})
+
+	start := time.Now()
+	err := mergeSources(ctx, []Source{staticSource("a: 1", "yaml"), slow}, newEmptyConfig())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "source 2")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
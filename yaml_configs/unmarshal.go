@@ -0,0 +1,109 @@
+package yaml_configs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalSlice decodes the list stored at the dotted key into target, which must
+// be a pointer to a slice. Items are decoded using their yaml struct tags.
+func (c *Config) UnmarshalSlice(key string, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("config key %s: target must be a non-nil pointer to a slice, got %T", key, target)
+	}
+
+	value, ok := c.lookup(key)
+	if !ok {
+		return fmt.Errorf("config key %s: not found", key)
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("config key %s: expected a sequence, got %T", key, value)
+	}
+
+	data, err := yaml.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("config key %s: %w", key, err)
+	}
+	if err := yaml.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("config key %s: %w", key, err)
+	}
+	return nil
+}
+
+// Unmarshal decodes the section stored at the dotted key into out, which must be a non-nil
+// pointer, using its yaml struct tags. An empty key decodes the whole config.
+func (c *Config) Unmarshal(key string, out any) error {
+	return c.unmarshal(key, out, false)
+}
+
+// UnmarshalStrict decodes like Unmarshal but fails when the section holds keys that have
+// no matching field in out, which catches misspelt settings
+func (c *Config) UnmarshalStrict(key string, out any) error {
+	return c.unmarshal(key, out, true)
+}
+
+// Require returns an error listing every key missing from the config, nil when all are set
+func (c *Config) Require(keys ...string) error {
+	var errs []error
+	for _, key := range keys {
+		if _, ok := c.lookup(key); !ok {
+			errs = append(errs, fmt.Errorf("config key %s: required but not set", key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unmarshal decodes the section at key into out, rejecting unknown fields when strict is set
+func (c *Config) unmarshal(key string, out any, strict bool) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("config key %s: target must be a non-nil pointer, got %T", key, out)
+	}
+
+	c.mu.RLock()
+	value, ok := c.section(key)
+	var data []byte
+	var err error
+	if ok {
+		data, err = yaml.Marshal(value)
+	}
+	c.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("config key %s: not found", key)
+	}
+	if err != nil {
+		return fmt.Errorf("config key %s: %w", key, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config key %s: %w", key, err)
+	}
+	return nil
+}
+
+// section walks the nested config along the dotted key, the caller must hold c.mu
+func (c *Config) section(key string) (any, bool) {
+	if key == "" {
+		return c.configMap, true
+	}
+	var current any = c.configMap
+	for _, part := range strings.Split(key, ".") {
+		nested, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = nested[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
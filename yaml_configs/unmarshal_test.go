@@ -0,0 +1,114 @@
+package yaml_configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type serverConfig struct {
+	Name string   `yaml:"name"`
+	Host string   `yaml:"host"`
+	Port int      `yaml:"port"`
+	Tags []string `yaml:"tags"`
+}
+
+func TestUnmarshalSlice(t *testing.T) {
+	cfg := newTestConfig(t, "./test_data/servers.yaml")
+
+	t.Run("decodes list of structs", func(t *testing.T) {
+		var servers []serverConfig
+		assert.NoError(t, cfg.UnmarshalSlice("app.servers", &servers))
+		assert.Equal(t, []serverConfig{
+			{Name: "primary", Host: "10.0.0.1", Port: 8080},
+			{Name: "replica", Host: "10.0.0.2", Port: 8081, Tags: []string{"read-only"}},
+		}, servers)
+	})
+
+	t.Run("not a sequence", func(t *testing.T) {
+		var servers []serverConfig
+		err := cfg.UnmarshalSlice("app.name", &servers)
+		assert.ErrorContains(t, err, "expected a sequence")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		var servers []serverConfig
+		err := cfg.UnmarshalSlice("app.clients", &servers)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("target is not a slice pointer", func(t *testing.T) {
+		var servers []serverConfig
+		err := cfg.UnmarshalSlice("app.servers", servers)
+		assert.ErrorContains(t, err, "pointer to a slice")
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	type dbConfig struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port"`
+		User     string `yaml:"user"`
+		PoolSize string `yaml:"pool_size"`
+	}
+	cfg := newTestConfig(t, "./test_data/env.local.yaml", MaxFlattenDepth(1))
+
+	t.Run("decodes a section", func(t *testing.T) {
+		var db dbConfig
+		assert.NoError(t, cfg.Unmarshal("database", &db))
+		assert.Equal(t, dbConfig{Host: "localhost", Port: 5430, User: "postgres", PoolSize: "10"}, db)
+	})
+
+	t.Run("empty key decodes everything", func(t *testing.T) {
+		var all struct {
+			Database dbConfig `yaml:"database"`
+		}
+		assert.NoError(t, cfg.Unmarshal("", &all))
+		assert.Equal(t, "localhost", all.Database.Host)
+	})
+
+	t.Run("nested scalar", func(t *testing.T) {
+		var port int
+		assert.NoError(t, cfg.Unmarshal("database.port", &port))
+		assert.Equal(t, 5430, port)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		var db dbConfig
+		assert.EqualError(t, cfg.Unmarshal("cache", &db), "config key cache: not found")
+		assert.EqualError(t, cfg.Unmarshal("database.host.name", &db), "config key database.host.name: not found")
+	})
+
+	t.Run("target is not a pointer", func(t *testing.T) {
+		assert.ErrorContains(t, cfg.Unmarshal("database", dbConfig{}), "non-nil pointer")
+	})
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	cfg := newTestConfig(t, "./test_data/servers.yaml")
+
+	var app struct {
+		Name    string         `yaml:"name"`
+		Servers []serverConfig `yaml:"servers"`
+	}
+	assert.NoError(t, cfg.UnmarshalStrict("app", &app))
+	assert.Equal(t, "gateway", app.Name)
+	assert.Len(t, app.Servers, 2)
+
+	var partial struct {
+		Name string `yaml:"name"`
+	}
+	assert.NoError(t, cfg.Unmarshal("app", &partial))
+	err := cfg.UnmarshalStrict("app", &partial)
+	assert.ErrorContains(t, err, "config key app:")
+	assert.ErrorContains(t, err, "field servers not found")
+}
+
+func TestRequire(t *testing.T) {
+	cfg := newTestConfig(t, "./test_data/servers.yaml")
+
+	assert.NoError(t, cfg.Require("app.name", "app.servers"))
+
+	err := cfg.Require("app.name", "app.port", "db.host")
+	assert.EqualError(t, err, "config key app.port: required but not set\nconfig key db.host: required but not set")
+}
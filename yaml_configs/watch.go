@@ -0,0 +1,148 @@
+package yaml_configs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is the quiet period Watch waits for after a file event before reloading
+const DefaultWatchDebounce = 100 * time.Millisecond
+
+// WithWatchDebounce sets how long Watch waits for file events to stop before reloading,
+// so an editor writing a file several times in a row causes a single reload
+func WithWatchDebounce(quiet time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.watchDebounce = quiet
+	}
+}
+
+// WithWatchErrorHandler sets fn to receive the errors met while watching, such as a reload
+// failing on invalid yaml. Without a handler these errors are dropped.
+func WithWatchErrorHandler(fn func(err error)) LoadOption {
+	return func(o *loadOptions) {
+		o.watchErrors = fn
+	}
+}
+
+// Watch reloads the config whenever one of the files it was loaded from changes and
+// then calls onReload, which may be nil. Bursts of events are coalesced into a single
+// reload, see WithWatchDebounce. The new values replace the loaded ones atomically,
+// so Get can be called while a reload happens, and values written through Set are applied
+// again on top of them. A reload that fails keeps the previous values, this includes a
+// loaded file that is missing, for instance while an editor replaces it.
+// Watching stops once ctx is done.
+func (c *Config) Watch(ctx context.Context, onReload func(cfg *Config)) error {
+	if len(c.paths) == 0 {
+		return errors.New("config was not loaded from files, nothing to watch")
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// Directories are watched rather than files so editors replacing a file are noticed
+	files := make(map[string]bool, len(c.paths))
+	for _, path := range c.paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			watcher.Close()
+			return err
+		}
+		files[abs] = true
+		if err := watcher.Add(filepath.Dir(abs)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+	}
+
+	go c.watch(ctx, watcher, files, onReload)
+	return nil
+}
+
+// watch reloads the config after events on files until ctx is done
+func (c *Config) watch(ctx context.Context, watcher *fsnotify.Watcher, files map[string]bool, onReload func(cfg *Config)) {
+	defer watcher.Close()
+
+	quiet := c.options.watchDebounce
+	if quiet <= 0 {
+		quiet = DefaultWatchDebounce
+	}
+	timer := time.NewTimer(quiet)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || !files[filepath.Clean(event.Name)] {
+				continue
+			}
+			timer.Reset(quiet)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			c.watchError(fmt.Errorf("watching config: %w", err))
+		case <-timer.C:
+			if err := c.reload(); err != nil {
+				c.watchError(fmt.Errorf("reloading config, keeping previous values: %w", err))
+				continue
+			}
+			if onReload != nil {
+				onReload(c)
+			}
+		}
+	}
+}
+
+// watchError hands err to the error handler set with WithWatchErrorHandler, if any
+func (c *Config) watchError(err error) {
+	if c.options.watchErrors != nil {
+		c.options.watchErrors(err)
+	}
+}
+
+// reload loads the config files again and swaps in the new values,
+// keeping the values written through Set
+func (c *Config) reload() error {
+	fresh, err := (&Loader{options: c.options}).Load(c.paths...)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Only files that were already missing may be skipped, dropping the values of a
+	// removed file would lose them until it is written again
+	for _, path := range c.paths {
+		if fresh.skipped[path] && !c.skipped[path] {
+			return fmt.Errorf("config file %s: %w", path, os.ErrNotExist)
+		}
+	}
+	c.skipped = fresh.skipped
+
+	// Apply the changes in a stable order so nested keys win over their parents
+	keys := make([]string, 0, len(c.changes))
+	for key := range c.changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		setNested(fresh.configMap, key, c.changes[key])
+	}
+	c.configMap = fresh.configMap
+	c.rebuildFlatMap()
+	return nil
+}
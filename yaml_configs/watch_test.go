@@ -0,0 +1,205 @@
+package yaml_configs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfig writes a yaml config with the given port to path
+func writeConfig(t *testing.T, path string, port int) {
+	data := fmt.Sprintf("database:\n  host: localhost\n  port: %d\n", port)
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	cfg, err := NewLoader(WithWatchDebounce(20 * time.Millisecond)).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan *Config, 1)
+	assert.NoError(t, cfg.Watch(ctx, func(c *Config) { reloaded <- c }))
+
+	writeConfig(t, path, 6000)
+	select {
+	case c := <-reloaded:
+		assert.Same(t, cfg, c)
+	case <-time.After(2 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	assert.Equal(t, 6000, cfg.Get("database.port"))
+	assert.Equal(t, "localhost", cfg.Get("database.host"))
+}
+
+func TestWatchDebouncesBursts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	cfg, err := NewLoader(WithWatchDebounce(100 * time.Millisecond)).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reloads atomic.Int32
+	assert.NoError(t, cfg.Watch(ctx, func(*Config) { reloads.Add(1) }))
+
+	// Rapid successive writes, like an editor truncating and then writing
+	for port := 6001; port <= 6005; port++ {
+		writeConfig(t, path, port)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool { return reloads.Load() > 0 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), reloads.Load())
+	assert.Equal(t, 6005, cfg.Get("database.port"))
+}
+
+func TestWatchConcurrentReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	cfg, err := NewLoader(WithWatchDebounce(time.Millisecond)).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reloads atomic.Int32
+	assert.NoError(t, cfg.Watch(ctx, func(*Config) { reloads.Add(1) }))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				cfg.Get("database.port")
+			}
+		}
+	}()
+	for port := 6001; port <= 6010; port++ {
+		writeConfig(t, path, port)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Eventually(t, func() bool { return cfg.Get("database.port") == 6010 }, 2*time.Second, 10*time.Millisecond)
+	close(done)
+	wg.Wait()
+	assert.Positive(t, reloads.Load())
+}
+
+func TestWatchStopsOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	cfg, err := NewLoader(WithWatchDebounce(10 * time.Millisecond)).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var reloads atomic.Int32
+	assert.NoError(t, cfg.Watch(ctx, func(*Config) { reloads.Add(1) }))
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	writeConfig(t, path, 6000)
+	time.Sleep(150 * time.Millisecond)
+	assert.Zero(t, reloads.Load())
+	assert.Equal(t, 5432, cfg.Get("database.port"))
+}
+
+func TestWatchWithoutFiles(t *testing.T) {
+	cfg, err := NewLoader().LoadSources(context.Background(), staticSource("a: 1", "yaml"))
+	assert.NoError(t, err)
+	assert.Error(t, cfg.Watch(context.Background(), nil))
+}
+
+func TestWatchKeepsSetValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	cfg, err := NewLoader(WithWatchDebounce(10 * time.Millisecond)).Load(path)
+	assert.NoError(t, err)
+	cfg.Set("database.host", "db.internal")
+	cfg.Set("features.search", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	assert.NoError(t, cfg.Watch(ctx, func(*Config) { reloaded <- struct{}{} }))
+
+	writeConfig(t, path, 6000)
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	assert.Equal(t, 6000, cfg.Get("database.port"))
+	assert.Equal(t, "db.internal", cfg.Get("database.host"))
+	assert.Equal(t, true, cfg.Get("features.search"))
+}
+
+func TestWatchErrorHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	errs := make(chan error, 1)
+	cfg, err := NewLoader(
+		WithWatchDebounce(10*time.Millisecond),
+		WithWatchErrorHandler(func(err error) { errs <- err }),
+	).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, cfg.Watch(ctx, nil))
+
+	assert.NoError(t, os.WriteFile(path, []byte("database: [5432"), 0o644))
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "reloading config, keeping previous values")
+	case <-time.After(2 * time.Second):
+		t.Fatal("reload error was not reported")
+	}
+	assert.Equal(t, 5432, cfg.Get("database.port"))
+}
+
+func TestWatchKeepsValuesOfRemovedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, 5432)
+	errs := make(chan error, 1)
+	reloaded := make(chan struct{}, 1)
+	cfg, err := NewLoader(
+		WithWatchDebounce(10*time.Millisecond),
+		WithWatchErrorHandler(func(err error) { errs <- err }),
+	).Load(path)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, cfg.Watch(ctx, func(cfg *Config) { reloaded <- struct{}{} }))
+
+	assert.NoError(t, os.Remove(path))
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	case <-time.After(2 * time.Second):
+		t.Fatal("missing file was not reported")
+	}
+	assert.Equal(t, 5432, cfg.Get("database.port"))
+
+	writeConfig(t, path, 6543)
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("recreated file was not reloaded")
+	}
+	assert.Equal(t, 6543, cfg.Get("database.port"))
+}
@@ -1,22 +1,141 @@
 package yaml_configs
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-var (
-	configDoOnce sync.Once
-	config       *Config
-)
+// defaultConfig is the config read by the package level Get
+var defaultConfig atomic.Pointer[Config]
 
 type Config struct {
+	// mu guards the maps so reads are safe while Watch reloads the config
+	mu            sync.RWMutex
 	configMap     map[string]any
 	configFlatMap map[string]any
+	changes       map[string]any
+	options       loadOptions
+	// paths are the files the config was loaded from, watched by Watch
+	paths []string
+	// skipped holds the paths that did not exist when the config was loaded
+	skipped map[string]bool
+}
+
+type loadOptions struct {
+	maxFlattenDepth int
+	mergeLogger     MergeLogger
+	watchDebounce   time.Duration
+	watchErrors     func(err error)
+	arrayMerge      ArrayMergeStrategy
+}
+
+// ArrayMergeStrategy decides how a list in a later file is merged with a list at the same key
+type ArrayMergeStrategy int
+
+const (
+	// ReplaceArrays keeps only the later list, the default
+	ReplaceArrays ArrayMergeStrategy = iota
+	// AppendArrays appends the later list to the earlier one
+	AppendArrays
+	// ConcatUnique appends the items of the later list that are not in the earlier one
+	ConcatUnique
+)
+
+// WithArrayMerge sets how lists found in several files are merged
+func WithArrayMerge(strategy ArrayMergeStrategy) LoadOption {
+	return func(o *loadOptions) {
+		o.arrayMerge = strategy
+	}
+}
+
+// MergeLogger is called whenever a later file replaces a value set by an earlier one.
+// key is the dotted path of the value and file names the file or source that replaced it.
+type MergeLogger func(key string, oldVal, newVal any, file string)
+
+// LoadOption customizes how config files are loaded
+type LoadOption func(*loadOptions)
+
+// MaxFlattenDepth limits how many levels of nesting are flattened into dotted keys.
+// Maps found at that depth are stored intact, so Get returns the whole subtree as a map[string]any.
+// A depth of 0 or less flattens everything, which is the default.
+func MaxFlattenDepth(depth int) LoadOption {
+	return func(o *loadOptions) {
+		o.maxFlattenDepth = depth
+	}
+}
+
+// WithMergeLogger reports every value overridden while configs are merged, to see which file won
+func WithMergeLogger(logger MergeLogger) LoadOption {
+	return func(o *loadOptions) {
+		o.mergeLogger = logger
+	}
+}
+
+// Loader loads configs with a fixed set of options.
+// Every load returns a new Config, so a Loader can be reused to load different files.
+type Loader struct {
+	options loadOptions
+}
+
+// NewLoader returns a Loader applying opts to every config it loads
+func NewLoader(opts ...LoadOption) *Loader {
+	l := &Loader{}
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+	return l
+}
+
+// Load loads configs in order, with later files overriding earlier ones. Missing files are skipped.
+func (l *Loader) Load(paths ...string) (*Config, error) {
+	cfg := newConfig(l.options)
+	cfg.paths = append([]string(nil), paths...)
+	// Load each config file in order
+	for _, path := range paths {
+		if err := loadAndMerge(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// LoadSources loads configs from sources in order like LoadConfigFromSources
+func (l *Loader) LoadSources(ctx context.Context, sources ...Source) (*Config, error) {
+	cfg := newConfig(l.options)
+	if err := mergeSources(ctx, sources, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// newConfig returns an empty config using options
+func newConfig(options loadOptions) *Config {
+	return &Config{
+		configMap:     make(map[string]any),
+		configFlatMap: make(map[string]any),
+		options:       options,
+	}
+}
+
+// Default returns the config read by the package level Get, nil until a config is loaded or set
+func Default() *Config {
+	return defaultConfig.Load()
+}
+
+// SetDefault makes cfg the config read by the package level Get
+func SetDefault(cfg *Config) {
+	defaultConfig.Store(cfg)
 }
 
 // LoadConfigWithSuffix loads a config file with a suffix, and overrides the config with the suffix file
@@ -29,29 +148,20 @@ func LoadConfigWithSuffix(path string, suffix string) (*Config, error) {
 	)
 }
 
-// LoadConfigWithOverrides loads configs in order, with later files overriding earlier ones
+// LoadConfigWithOverrides loads configs in order, with later files overriding earlier ones.
+// Every call returns a new Config and makes it the default read by Get.
 func LoadConfigWithOverrides(paths ...string) (*Config, error) {
-	var loadErr error
-
-	configDoOnce.Do(func() {
-		config = &Config{
-			configMap:     make(map[string]any),
-			configFlatMap: make(map[string]any),
-		}
-
-		// Load each config file in order
-		for _, path := range paths {
-			if err := loadAndMerge(path, config); err != nil {
-				loadErr = err
-				return
-			}
-		}
-	})
+	return LoadConfigWithOptions(paths)
+}
 
-	if loadErr != nil {
-		return nil, loadErr
+// LoadConfigWithOptions loads configs in order like LoadConfigWithOverrides, applying the given options
+func LoadConfigWithOptions(paths []string, opts ...LoadOption) (*Config, error) {
+	cfg, err := NewLoader(opts...).Load(paths...)
+	if err != nil {
+		return nil, err
 	}
-	return config, nil
+	SetDefault(cfg)
+	return cfg, nil
 }
 
 func loadAndMerge(path string, cfg *Config) error {
@@ -60,45 +170,98 @@ func loadAndMerge(path string, cfg *Config) error {
 		if os.IsNotExist(err) {
 			// Skip if file doesn't exist
 			fmt.Printf("File %s does not exist, skipping\n", path)
+			if cfg.skipped == nil {
+				cfg.skipped = make(map[string]bool)
+			}
+			cfg.skipped[path] = true
 			return nil
 		}
 		return err
 	}
 	defer yamlFile.Close()
 
+	return mergeReader(yamlFile, path, cfg)
+}
+
+// mergeReader decodes a yaml document from r and merges it into cfg.
+// An empty document leaves cfg unchanged, file names the document for the merge logger.
+func mergeReader(r io.Reader, file string, cfg *Config) error {
 	var newConfig map[string]any
-	if err := yaml.NewDecoder(yamlFile).Decode(&newConfig); err != nil {
+	if err := yaml.NewDecoder(r).Decode(&newConfig); err != nil && !errors.Is(err, io.EOF) {
 		return err
 	}
 
 	// Merge new config into existing
-	mergeMap(cfg.configMap, newConfig)
+	var onReplace func(key string, oldVal, newVal any)
+	if logger := cfg.options.mergeLogger; logger != nil {
+		onReplace = func(key string, oldVal, newVal any) {
+			logger(key, oldVal, newVal, file)
+		}
+	}
+	mergeMap(cfg.configMap, newConfig, "", cfg.options.arrayMerge, onReplace)
 
 	// Rebuild flat map
-	cfg.configFlatMap = make(map[string]any)
-	flattenConfig(cfg.configMap, "", cfg.configFlatMap)
+	cfg.rebuildFlatMap()
 
 	return nil
 }
 
-// mergeMap recursively merges src into dst
-func mergeMap(dst, src map[string]any) {
+// mergeMap recursively merges src into dst, combining lists with arrays and calling
+// onReplace (when set) with the dotted key of every existing value that gets overridden
+func mergeMap(dst, src map[string]any, prefix string, arrays ArrayMergeStrategy, onReplace func(key string, oldVal, newVal any)) {
 	for key, srcVal := range src {
 		if dstVal, exists := dst[key]; exists {
+			fullKey := key
+			if prefix != "" {
+				fullKey = fmt.Sprintf("%s.%s", prefix, key)
+			}
 			// If both are maps, merge recursively
 			if dstMap, ok := dstVal.(map[string]any); ok {
 				if srcMap, ok := srcVal.(map[string]any); ok {
-					mergeMap(dstMap, srcMap)
+					mergeMap(dstMap, srcMap, fullKey, arrays, onReplace)
 					continue
 				}
 			}
+			if dstList, ok := dstVal.([]any); ok {
+				if srcList, ok := srcVal.([]any); ok {
+					srcVal = mergeLists(dstList, srcList, arrays)
+				}
+			}
+			if onReplace != nil {
+				onReplace(fullKey, dstVal, srcVal)
+			}
 		}
 		// Otherwise override the value
 		dst[key] = srcVal
 	}
 }
 
-func flattenConfig(configMap map[string]any, prefix string, flatMap map[string]any) {
+// mergeLists combines the lists found at the same key according to strategy
+func mergeLists(dst, src []any, strategy ArrayMergeStrategy) []any {
+	switch strategy {
+	case AppendArrays:
+		return append(append([]any(nil), dst...), src...)
+	case ConcatUnique:
+		merged := append([]any(nil), dst...)
+		for _, item := range src {
+			if !slices.ContainsFunc(merged, func(existing any) bool { return reflect.DeepEqual(existing, item) }) {
+				merged = append(merged, item)
+			}
+		}
+		return merged
+	}
+	return src
+}
+
+// rebuildFlatMap recomputes the flat lookup map from the nested config
+func (c *Config) rebuildFlatMap() {
+	c.configFlatMap = make(map[string]any)
+	flattenConfig(c.configMap, "", c.configFlatMap, 1, c.options.maxFlattenDepth)
+}
+
+// flattenConfig stores every leaf of configMap under its dotted key.
+// Once depth reaches maxDepth (when positive) nested maps are kept as leaves.
+func flattenConfig(configMap map[string]any, prefix string, flatMap map[string]any, depth int, maxDepth int) {
 	for key, value := range configMap {
 		var newKey string
 		if prefix == "" {
@@ -106,8 +269,8 @@ func flattenConfig(configMap map[string]any, prefix string, flatMap map[string]a
 		} else {
 			newKey = fmt.Sprintf("%s.%s", prefix, key)
 		}
-		if nestedMap, ok := value.(map[string]any); ok {
-			flattenConfig(nestedMap, newKey, flatMap)
+		if nestedMap, ok := value.(map[string]any); ok && (maxDepth <= 0 || depth < maxDepth) {
+			flattenConfig(nestedMap, newKey, flatMap, depth+1, maxDepth)
 		} else {
 
 			flatMap[newKey] = value
@@ -119,13 +282,14 @@ func flattenConfig(configMap map[string]any, prefix string, flatMap map[string]a
 }
 
 func (c *Config) Get(key string) any {
-	return c.configFlatMap[key]
+	value, _ := c.lookup(key)
+	return value
 }
 
-func Get[T any](key string) T {
-	value, ok := config.configFlatMap[key]
-	if !ok {
-		return *new(T)
-	}
-	return value.(T)
+// lookup returns the value stored at the flattened key
+func (c *Config) lookup(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.configFlatMap[key]
+	return value, ok
 }
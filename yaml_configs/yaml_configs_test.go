@@ -3,8 +3,19 @@ package yaml_configs
 import (
 	"fmt"
 	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
+// newTestConfig loads path into a fresh Config without touching the default config
+func newTestConfig(t *testing.T, path string, opts ...LoadOption) *Config {
+	cfg, err := NewLoader(opts...).Load(path)
+	assert.NoError(t, err)
+	return cfg
+}
+
 func ExampleLoadConfigWithOverrides() {
 	// Load configs in order of precedence
 	_, err := LoadConfigWithSuffix(
@@ -27,3 +38,135 @@ func ExampleLoadConfigWithOverrides() {
 	// postgres
 	// postgres
 }
+
+func TestMaxFlattenDepth(t *testing.T) {
+	t.Run("default flattens everything", func(t *testing.T) {
+		cfg := newTestConfig(t, "./test_data/env.yaml")
+		assert.Equal(t, "localhost", cfg.Get("database.host"))
+		assert.Nil(t, cfg.Get("database"))
+	})
+
+	t.Run("depth 1 keeps top level subtrees", func(t *testing.T) {
+		cfg := newTestConfig(t, "./test_data/env.yaml", MaxFlattenDepth(1))
+		database, ok := cfg.Get("database").(map[string]any)
+		assert.True(t, ok)
+		assert.Equal(t, "localhost", database["host"])
+		assert.Equal(t, 5432, database["port"])
+		assert.Equal(t, database, cfg.Get("DATABASE"))
+		assert.Nil(t, cfg.Get("database.host"))
+	})
+
+	t.Run("depth beyond nesting flattens everything", func(t *testing.T) {
+		cfg := newTestConfig(t, "./test_data/env.yaml", MaxFlattenDepth(5))
+		assert.Equal(t, "localhost", cfg.Get("database.host"))
+	})
+}
+
+func TestGetCoercesQuotedValues(t *testing.T) {
+	_, err := LoadConfigWithSuffix("./test_data/env", "local")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 10, Get[int]("database.pool_size"))
+	assert.Equal(t, int64(10), Get[int64]("database.pool_size"))
+	assert.Equal(t, uint16(10), Get[uint16]("database.pool_size"))
+	assert.Equal(t, "10", Get[string]("database.pool_size"))
+	assert.Equal(t, true, Get[bool]("database.ssl"))
+	assert.Equal(t, 2.5, Get[float64]("database.timeout_seconds"))
+
+	assert.PanicsWithValue(t, `config key database.host: cannot coerce "localhost" to int: strconv.ParseInt: parsing "localhost": invalid syntax`, func() {
+		Get[int]("database.host")
+	})
+	assert.Panics(t, func() { Get[string]("database.port") })
+}
+
+func TestCoerce(t *testing.T) {
+	i, err := coerce[int]("5430")
+	assert.NoError(t, err)
+	assert.Equal(t, 5430, i)
+
+	_, err = coerce[int8]("300")
+	assert.Error(t, err)
+
+	_, err = coerce[bool]("maybe")
+	assert.Error(t, err)
+
+	_, err = coerce[[]string]("a")
+	assert.Error(t, err)
+}
+
+func TestWithMergeLogger(t *testing.T) {
+	type replacement struct {
+		oldVal, newVal any
+		file           string
+	}
+	replaced := map[string]replacement{}
+	logger := WithMergeLogger(func(key string, oldVal, newVal any, file string) {
+		replaced[key] = replacement{oldVal: oldVal, newVal: newVal, file: file}
+	})
+
+	cfg := newTestConfig(t, "./test_data/env.yaml", logger)
+	assert.Empty(t, replaced)
+
+	assert.NoError(t, loadAndMerge("./test_data/env.local.yaml", cfg))
+	assert.Equal(t, replacement{oldVal: 5432, newVal: 5430, file: "./test_data/env.local.yaml"}, replaced["database.port"])
+	assert.Contains(t, replaced, "database.host")
+	assert.NotContains(t, replaced, "database")
+	assert.NotContains(t, replaced, "database.pool_size")
+	assert.Equal(t, 5430, cfg.Get("database.port"))
+}
+
+func TestLoadConfigReloads(t *testing.T) {
+	base, err := LoadConfigWithOverrides("./test_data/env.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, Get[int]("database.port"))
+
+	local, err := LoadConfigWithSuffix("./test_data/env", "local")
+	assert.NoError(t, err)
+	assert.NotSame(t, base, local)
+	assert.Equal(t, 5432, base.Get("database.port"))
+	assert.Equal(t, 5430, local.Get("database.port"))
+	assert.Equal(t, 5430, Get[int]("database.port"))
+
+	SetDefault(base)
+	assert.Same(t, base, Default())
+	assert.Equal(t, 5432, Get[int]("database.port"))
+}
+
+func TestLoaderLeavesDefaultAlone(t *testing.T) {
+	SetDefault(nil)
+	cfg, err := NewLoader().Load("./test_data/env.yaml", "./test_data/env.local.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 5430, cfg.Get("database.port"))
+	assert.Nil(t, Default())
+	assert.Zero(t, Get[int]("database.port"))
+}
+
+func TestWithArrayMerge(t *testing.T) {
+	base := "cors:\n  allowed_origins: [a.com, b.com]\n  max_age: 60\n"
+	override := "cors:\n  allowed_origins: [b.com, c.com]\n"
+
+	tests := []struct {
+		name     string
+		opts     []LoadOption
+		expected []any
+	}{
+		{name: "replace by default", expected: []any{"b.com", "c.com"}},
+		{name: "replace", opts: []LoadOption{WithArrayMerge(ReplaceArrays)}, expected: []any{"b.com", "c.com"}},
+		{name: "append", opts: []LoadOption{WithArrayMerge(AppendArrays)}, expected: []any{"a.com", "b.com", "b.com", "c.com"}},
+		{name: "concat unique", opts: []LoadOption{WithArrayMerge(ConcatUnique)}, expected: []any{"a.com", "b.com", "c.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewLoader(tt.opts...).LoadReaders(strings.NewReader(base), strings.NewReader(override))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Get("cors.allowed_origins"))
+			assert.Equal(t, 60, cfg.Get("cors.max_age"))
+		})
+	}
+
+	t.Run("list replacing a scalar", func(t *testing.T) {
+		cfg, err := NewLoader(WithArrayMerge(AppendArrays)).LoadReaders(strings.NewReader("tags: none\n"), strings.NewReader("tags: [x]\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"x"}, cfg.Get("tags"))
+	})
+}